@@ -10,9 +10,17 @@ import (
 
 // App config struct
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logger   Logger
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Logger    Logger
+	OIDC      OIDCConfig
+	Auth      AuthConfig
+	Mail      MailConfig
+	Booking   BookingConfig
+	Scheduler SchedulerConfig
+	Webhook   WebhookConfig
+	Assets    AssetsConfig
+	Storage   StorageConfig
 }
 
 // Server config struct
@@ -24,6 +32,136 @@ type ServerConfig struct {
 	WriteTimeout      time.Duration
 	CtxDefaultTimeout time.Duration
 	Debug             bool
+	PublicURL         string
+}
+
+// Password login config, backends are tried in the listed order
+type AuthConfig struct {
+	Backends []string
+	LDAP     LDAPConfig
+	// Registration requires an email in one of these domains when set
+	AllowedEmailDomains []string
+	// Email verification token lifetime in hours
+	EmailVerificationTTL time.Duration
+}
+
+// LDAP simple bind backend config
+type LDAPConfig struct {
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+	BindDN             string
+	BindPassword       string
+	UserBaseDN         string
+	UserFilter         string
+	LoginAttribute     string
+	EmailAttribute     string
+	GroupBaseDN        string
+	GroupFilter        string
+	GroupNameAttribute string
+	DefaultRole        string
+	RoleMappings       []RoleMapping
+}
+
+// Outgoing mail config, "log" driver only writes messages to the log
+type MailConfig struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	// Seconds a message may take to hand over to the SMTP server
+	Timeout time.Duration
+}
+
+// OpenID Connect single sign-on config
+type OIDCConfig struct {
+	Enabled      bool
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	DefaultRole  string
+	RoleMappings []RoleMapping
+}
+
+// Maps an external group to EquipTrack role, first match wins
+type RoleMapping struct {
+	Group string
+	Role  string
+}
+
+// Reservation rules, every policy matching the equipment type and user role applies
+type BookingConfig struct {
+	Policies []BookingPolicy
+}
+
+// Empty EquipmentType or Role matches any, zero limits are not checked. Durations are in hours
+type BookingPolicy struct {
+	Name             string
+	EquipmentType    string
+	Role             string
+	MaxDuration      time.Duration
+	MinAdvance       time.Duration
+	MaxAdvance       time.Duration
+	MaxConcurrent    int
+	MaxHoursPerMonth float64
+}
+
+// Background jobs, with several replicas only one of them runs the jobs at a time
+type SchedulerConfig struct {
+	Enabled bool
+	// Seconds between runs
+	Interval time.Duration
+	// Minutes before reservation start or end the reminder is sent
+	ReminderLead time.Duration
+}
+
+// Outbound webhook delivery, failed attempts are retried after BackoffBase * 2^(attempt-1) seconds
+type WebhookConfig struct {
+	// Seconds to wait for the receiver
+	Timeout     time.Duration
+	MaxAttempts int
+	BackoffBase time.Duration
+}
+
+// Asset identification. Generated asset tags are TagPrefix followed by a sequence number,
+// LabelURL is encoded in QR labels with %s replaced by the asset tag and defaults to the lookup endpoint
+type AssetsConfig struct {
+	TagPrefix string
+	LabelURL  string
+	// Label sheet layouts in addition to the built in ones, a template with a built in name replaces it
+	LabelTemplates []LabelTemplate
+}
+
+// Layout of an adhesive label sheet, all lengths are in millimetres
+type LabelTemplate struct {
+	Name        string  `json:"name"`
+	PageWidth   float64 `json:"page_width"`
+	PageHeight  float64 `json:"page_height"`
+	Columns     int     `json:"columns"`
+	Rows        int     `json:"rows"`
+	LabelWidth  float64 `json:"label_width"`
+	LabelHeight float64 `json:"label_height"`
+	MarginTop   float64 `json:"margin_top"`
+	MarginLeft  float64 `json:"margin_left"`
+	// Space between neighbouring labels
+	GapX float64 `json:"gap_x"`
+	GapY float64 `json:"gap_y"`
+}
+
+// Uploaded files, Driver is "local" for now
+type StorageConfig struct {
+	Driver    string
+	LocalPath string
+	// Megabytes
+	MaxUploadSize int64
+	// Longest side of generated thumbnails in pixels
+	ThumbnailSize int
 }
 
 // Logger config
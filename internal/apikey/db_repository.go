@@ -0,0 +1,22 @@
+package apikey
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	CreateServiceAccount(ctx context.Context, account *models.ServiceAccount, passwordHash string) (*models.ServiceAccount, error)
+	DeleteServiceAccount(ctx context.Context, userID uuid.UUID) error
+	GetServiceAccount(ctx context.Context, userID uuid.UUID) (*models.ServiceAccount, error)
+	GetServiceAccounts(ctx context.Context, pq *utils.PaginationQuery) (*models.ServiceAccountList, error)
+
+	CreateKey(ctx context.Context, key *models.APIKey) (*models.APIKey, error)
+	GetKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	GetKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	RevokeKey(ctx context.Context, userID uuid.UUID, keyID uuid.UUID) error
+	TouchKey(ctx context.Context, keyID uuid.UUID) error
+}
@@ -0,0 +1,13 @@
+package apikey
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	CreateServiceAccount() echo.HandlerFunc
+	DeleteServiceAccount() echo.HandlerFunc
+	GetServiceAccounts() echo.HandlerFunc
+
+	CreateKey() echo.HandlerFunc
+	GetKeys() echo.HandlerFunc
+	RevokeKey() echo.HandlerFunc
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"equiptrack/config"
+	"equiptrack/internal/apikey"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type apiKeyHandlers struct {
+	cfg      *config.Config
+	apiKeyUC apikey.UseCase
+	logger   *logrus.Logger
+}
+
+// NewAPIKeyHandlers API key handlers constructor
+func NewAPIKeyHandlers(cfg *config.Config, apiKeyUC apikey.UseCase, log *logrus.Logger) apikey.Handlers {
+	return &apiKeyHandlers{cfg: cfg, apiKeyUC: apiKeyUC, logger: log}
+}
+
+func (h *apiKeyHandlers) CreateServiceAccount() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		account := &models.ServiceAccount{}
+		if err := utils.ReadRequest(c, account); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		createdAccount, err := h.apiKeyUC.CreateServiceAccount(c.Request().Context(), account)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusCreated, createdAccount)
+	}
+}
+
+func (h *apiKeyHandlers) DeleteServiceAccount() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		uID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.apiKeyUC.DeleteServiceAccount(c.Request().Context(), uID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *apiKeyHandlers) GetServiceAccounts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		accountList, err := h.apiKeyUC.GetServiceAccounts(c.Request().Context(), paginationQuery)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, accountList)
+	}
+}
+
+func (h *apiKeyHandlers) CreateKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		uID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		key := &models.APIKey{}
+		if err := utils.ReadRequest(c, key); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+		key.UserID = uID
+
+		createdKey, err := h.apiKeyUC.CreateKey(c.Request().Context(), key)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusCreated, createdKey)
+	}
+}
+
+func (h *apiKeyHandlers) GetKeys() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		uID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		keys, err := h.apiKeyUC.GetKeys(c.Request().Context(), uID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, keys)
+	}
+}
+
+func (h *apiKeyHandlers) RevokeKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		uID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		kID, err := uuid.Parse(c.Param("key_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.apiKeyUC.RevokeKey(c.Request().Context(), uID, kID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
@@ -0,0 +1,19 @@
+package http
+
+import (
+	"equiptrack/internal/apikey"
+	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MapServiceAccountRoutes(saGroup *echo.Group, h apikey.Handlers, mw *middleware.MiddlewareManager) {
+	saGroup.Use(mw.AuthJWTMiddleware, mw.IsAdminMiddleware, mw.RequireScope(models.ScopeUsersWrite))
+	saGroup.POST("", h.CreateServiceAccount())
+	saGroup.GET("", h.GetServiceAccounts())
+	saGroup.DELETE("/:user_id", h.DeleteServiceAccount())
+	saGroup.POST("/:user_id/keys", h.CreateKey())
+	saGroup.GET("/:user_id/keys", h.GetKeys())
+	saGroup.DELETE("/:user_id/keys/:key_id", h.RevokeKey())
+}
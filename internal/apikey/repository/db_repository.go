@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/internal/apikey"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type apiKeyRepo struct {
+	db *sql.DB
+}
+
+// API key Repository constructor
+func NewAPIKeyRepository(db *sql.DB) apikey.Repository {
+	return &apiKeyRepo{db: db}
+}
+
+func (r *apiKeyRepo) CreateServiceAccount(ctx context.Context, account *models.ServiceAccount, passwordHash string) (*models.ServiceAccount, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.CreateServiceAccount.BeginTx")
+	}
+	defer tx.Rollback()
+
+	sa := &models.ServiceAccount{
+		Login:       account.Login,
+		Role:        account.Role,
+		Description: account.Description,
+	}
+	if err := tx.QueryRowContext(ctx, qCreateServiceUser, sa.Login, passwordHash, sa.Role).Scan(&sa.UserID); err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.CreateServiceAccount.CreateUser")
+	}
+	if err := tx.QueryRowContext(ctx, qCreateServiceAccount, sa.UserID, sa.Description).Scan(&sa.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.CreateServiceAccount.CreateAccount")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.CreateServiceAccount.Commit")
+	}
+	return sa, nil
+}
+
+func (r *apiKeyRepo) DeleteServiceAccount(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "apiKeyRepo.DeleteServiceAccount.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, qDeleteServiceKeys, userID); err != nil {
+		return errors.Wrap(err, "apiKeyRepo.DeleteServiceAccount.DeleteKeys")
+	}
+	result, err := tx.ExecContext(ctx, qDeleteServiceAccount, userID)
+	if err != nil {
+		return errors.Wrap(err, "apiKeyRepo.DeleteServiceAccount.DeleteAccount")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "apiKeyRepo.DeleteServiceAccount.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "apiKeyRepo.DeleteServiceAccount.rowsAffected")
+	}
+	if _, err := tx.ExecContext(ctx, qDeleteServiceUser, userID); err != nil {
+		return errors.Wrap(err, "apiKeyRepo.DeleteServiceAccount.DeleteUser")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "apiKeyRepo.DeleteServiceAccount.Commit")
+	}
+	return nil
+}
+
+func (r *apiKeyRepo) GetServiceAccount(ctx context.Context, userID uuid.UUID) (*models.ServiceAccount, error) {
+	sa := &models.ServiceAccount{}
+	if err := r.db.QueryRowContext(ctx, qGetServiceAccount, userID).Scan(
+		&sa.UserID,
+		&sa.Login,
+		&sa.Role,
+		&sa.Description,
+		&sa.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.GetServiceAccount.QueryRowContext")
+	}
+	return sa, nil
+}
+
+func (r *apiKeyRepo) getTotalCount(ctx context.Context) (int, error) {
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, qGetTotal).Scan(&totalCount); err != nil {
+		return 0, errors.Wrap(err, "apiKeyRepo.getTotalCount.QueryRowContext")
+	}
+	return totalCount, nil
+}
+
+func (r *apiKeyRepo) GetServiceAccounts(ctx context.Context, pq *utils.PaginationQuery) (*models.ServiceAccountList, error) {
+	totalCount, err := r.getTotalCount(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.GetServiceAccounts.totalCount")
+	}
+
+	if totalCount == 0 {
+		return &models.ServiceAccountList{
+			TotalCount:      totalCount,
+			TotalPages:      utils.GetTotalPages(totalCount, pq.GetSize()),
+			Page:            pq.GetPage(),
+			Size:            pq.GetSize(),
+			HasMore:         utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+			ServiceAccounts: make([]models.ServiceAccount, 0),
+		}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, qGetServiceAccounts, pq.GetOffset(), pq.GetLimit())
+	if err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.GetServiceAccounts.QueryContext")
+	}
+	defer rows.Close()
+
+	var accounts = make([]models.ServiceAccount, 0, pq.GetSize())
+	for rows.Next() {
+		var sa models.ServiceAccount
+		if err := rows.Scan(&sa.UserID, &sa.Login, &sa.Role, &sa.Description, &sa.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "apiKeyRepo.GetServiceAccounts.QueryContext.ScanRows")
+		}
+		accounts = append(accounts, sa)
+	}
+
+	return &models.ServiceAccountList{
+		TotalCount:      totalCount,
+		TotalPages:      utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:            pq.GetPage(),
+		Size:            pq.GetSize(),
+		HasMore:         utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		ServiceAccounts: accounts,
+	}, nil
+}
+
+func (r *apiKeyRepo) CreateKey(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	k := *key
+	if err := r.db.QueryRowContext(
+		ctx, qCreateKey, key.UserID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.ExpiresAt,
+	).Scan(&k.KeyID, &k.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.CreateKey.QueryRowContext")
+	}
+	return &k, nil
+}
+
+func (r *apiKeyRepo) GetKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, qGetKeys, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.GetKeys.QueryContext")
+	}
+	defer rows.Close()
+
+	var keys = make([]models.APIKey, 0)
+	for rows.Next() {
+		var k models.APIKey
+		if err := rows.Scan(
+			&k.KeyID,
+			&k.UserID,
+			&k.Name,
+			&k.Prefix,
+			pq.Array(&k.Scopes),
+			&k.CreatedAt,
+			&k.ExpiresAt,
+			&k.LastUsedAt,
+			&k.RevokedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "apiKeyRepo.GetKeys.QueryContext.ScanRows")
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepo) GetKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	k := &models.APIKey{}
+	if err := r.db.QueryRowContext(ctx, qGetKeyByPrefix, prefix).Scan(
+		&k.KeyID,
+		&k.UserID,
+		&k.Name,
+		&k.Prefix,
+		&k.KeyHash,
+		pq.Array(&k.Scopes),
+		&k.CreatedAt,
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.RevokedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "apiKeyRepo.GetKeyByPrefix.QueryRowContext")
+	}
+	return k, nil
+}
+
+func (r *apiKeyRepo) RevokeKey(ctx context.Context, userID uuid.UUID, keyID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qRevokeKey, userID, keyID)
+	if err != nil {
+		return errors.Wrap(err, "apiKeyRepo.RevokeKey.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "apiKeyRepo.RevokeKey.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "apiKeyRepo.RevokeKey.rowsAffected")
+	}
+	return nil
+}
+
+func (r *apiKeyRepo) TouchKey(ctx context.Context, keyID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, qTouchKey, keyID); err != nil {
+		return errors.Wrap(err, "apiKeyRepo.TouchKey.ExecContext")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+const (
+	qCreateServiceUser    = `INSERT INTO users (login, password, role) VALUES ($1, $2, $3) RETURNING user_id`
+	qCreateServiceAccount = `INSERT INTO service_accounts (user_id, description) VALUES ($1, $2) RETURNING created_at`
+	qDeleteServiceKeys    = `DELETE FROM api_keys WHERE user_id = $1`
+	qDeleteServiceAccount = `DELETE FROM service_accounts WHERE user_id = $1`
+	qDeleteServiceUser    = `DELETE FROM users WHERE user_id = $1`
+	qGetServiceAccount    = `SELECT user_id, login, role, description, created_at
+	FROM service_accounts
+	INNER JOIN users using(user_id)
+	WHERE user_id = $1`
+
+	qGetTotal           = `SELECT COUNT(user_id) FROM service_accounts`
+	qGetServiceAccounts = `SELECT user_id, login, role, description, created_at
+	FROM service_accounts
+	INNER JOIN users using(user_id)
+	ORDER BY created_at
+	OFFSET $1
+	LIMIT $2`
+
+	qCreateKey = `INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING key_id, created_at`
+	qGetKeys = `SELECT key_id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+	FROM api_keys
+	WHERE user_id = $1
+	ORDER BY created_at`
+	qGetKeyByPrefix = `SELECT key_id, user_id, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at
+	FROM api_keys
+	WHERE prefix = $1`
+	qRevokeKey = `UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+	WHERE user_id = $1 AND key_id = $2 AND revoked_at IS NULL`
+	qTouchKey = `UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE key_id = $1`
+)
@@ -0,0 +1,20 @@
+package apikey
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type UseCase interface {
+	CreateServiceAccount(ctx context.Context, account *models.ServiceAccount) (*models.ServiceAccount, error)
+	DeleteServiceAccount(ctx context.Context, userID uuid.UUID) error
+	GetServiceAccounts(ctx context.Context, pq *utils.PaginationQuery) (*models.ServiceAccountList, error)
+
+	CreateKey(ctx context.Context, key *models.APIKey) (*models.APIKeyWithSecret, error)
+	GetKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	RevokeKey(ctx context.Context, userID uuid.UUID, keyID uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
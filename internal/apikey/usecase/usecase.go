@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"equiptrack/config"
+	"equiptrack/internal/apikey"
+	"equiptrack/internal/audit"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultServiceRole = "service"
+	// last_used_at is only tracked with minute precision to spare writes on every request
+	touchInterval = time.Minute
+)
+
+type apiKeyUC struct {
+	cfg        *config.Config
+	apiKeyRepo apikey.Repository
+	auditor    audit.Recorder
+	logger     *logrus.Logger
+}
+
+func NewAPIKeyUseCase(
+	cfg *config.Config,
+	apiKeyRepo apikey.Repository,
+	auditor audit.Recorder,
+	log *logrus.Logger,
+) apikey.UseCase {
+	return &apiKeyUC{cfg: cfg, apiKeyRepo: apiKeyRepo, auditor: auditor, logger: log}
+}
+
+func (u *apiKeyUC) CreateServiceAccount(ctx context.Context, account *models.ServiceAccount) (*models.ServiceAccount, error) {
+	if account.Role == "" {
+		account.Role = defaultServiceRole
+	}
+
+	// service accounts never log in with a password, so store a random one
+	password, err := utils.NewRefreshToken()
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "apiKeyUC.CreateServiceAccount.NewRefreshToken"))
+	}
+	user := &models.User{Login: account.Login, Password: password, Role: account.Role}
+	if err = user.PrepareCreate(); err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "apiKeyUC.CreateServiceAccount.PrepareCreate"))
+	}
+	account.Role = user.Role
+
+	created, err := u.apiKeyRepo.CreateServiceAccount(ctx, account, user.Password)
+	if err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityServiceAccount, created.UserID.String(), nil, created)
+	return created, nil
+}
+
+func (u *apiKeyUC) DeleteServiceAccount(ctx context.Context, userID uuid.UUID) error {
+	before, err := u.apiKeyRepo.GetServiceAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err = u.apiKeyRepo.DeleteServiceAccount(ctx, userID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityServiceAccount, userID.String(), before, nil)
+	return nil
+}
+
+func (u *apiKeyUC) GetServiceAccounts(ctx context.Context, pq *utils.PaginationQuery) (*models.ServiceAccountList, error) {
+	return u.apiKeyRepo.GetServiceAccounts(ctx, pq)
+}
+
+func (u *apiKeyUC) CreateKey(ctx context.Context, key *models.APIKey) (*models.APIKeyWithSecret, error) {
+	if _, err := u.apiKeyRepo.GetServiceAccount(ctx, key.UserID); err != nil {
+		return nil, err
+	}
+	for _, scope := range key.Scopes {
+		if !slices.Contains(models.APIKeyScopes, scope) {
+			return nil, httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, "unknown scope: "+scope, nil)
+		}
+	}
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
+		return nil, httpErrors.NewBadRequestError("expires_at is in the past")
+	}
+
+	prefix, plainKey, err := utils.NewAPIKey()
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "apiKeyUC.CreateKey.NewAPIKey"))
+	}
+	key.Prefix = prefix
+	key.KeyHash = utils.HashAPIKey(plainKey)
+
+	createdKey, err := u.apiKeyRepo.CreateKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityAPIKey, createdKey.KeyID.String(), nil, createdKey)
+
+	return &models.APIKeyWithSecret{APIKey: createdKey, Key: plainKey}, nil
+}
+
+func (u *apiKeyUC) GetKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	return u.apiKeyRepo.GetKeys(ctx, userID)
+}
+
+func (u *apiKeyUC) RevokeKey(ctx context.Context, userID uuid.UUID, keyID uuid.UUID) error {
+	if err := u.apiKeyRepo.RevokeKey(ctx, userID, keyID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditRevoke, models.AuditEntityAPIKey, keyID.String(), nil, nil)
+	return nil
+}
+
+func (u *apiKeyUC) Authenticate(ctx context.Context, plainKey string) (*models.APIKey, error) {
+	prefix, err := utils.ParseAPIKeyPrefix(plainKey)
+	if err != nil {
+		return nil, httpErrors.InvalidAPIKey
+	}
+
+	key, err := u.apiKeyRepo.GetKeyByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, errors.Wrap(httpErrors.InvalidAPIKey, err.Error())
+	}
+	if !utils.CompareAPIKey(key.KeyHash, plainKey) || !key.IsActive() {
+		return nil, httpErrors.InvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > touchInterval {
+		if err := u.apiKeyRepo.TouchKey(ctx, key.KeyID); err != nil {
+			u.logger.Errorf("apiKeyUC.Authenticate.TouchKey: %v", err)
+		}
+	}
+
+	return key, nil
+}
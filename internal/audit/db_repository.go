@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+)
+
+// Audit entries are only ever inserted, there is no update or delete
+type Repository interface {
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	GetEntries(ctx context.Context, filter *models.AuditFilter, pq *utils.PaginationQuery) (*models.AuditList, error)
+}
@@ -0,0 +1,7 @@
+package audit
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	GetEntries() echo.HandlerFunc
+}
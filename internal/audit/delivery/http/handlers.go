@@ -0,0 +1,74 @@
+package http
+
+import (
+	"equiptrack/config"
+	"equiptrack/internal/audit"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type auditHandlers struct {
+	cfg     *config.Config
+	auditUC audit.UseCase
+	logger  *logrus.Logger
+}
+
+// NewAuditHandlers Audit handlers constructor
+func NewAuditHandlers(cfg *config.Config, auditUC audit.UseCase, log *logrus.Logger) audit.Handlers {
+	return &auditHandlers{cfg: cfg, auditUC: auditUC, logger: log}
+}
+
+// Newest entries first, filtered by actor_id, action, entity_type, entity_id
+// and the RFC 3339 from/to bounds of the creation time
+func (h *auditHandlers) GetEntries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		filter := &models.AuditFilter{
+			Action:     c.QueryParam("action"),
+			EntityType: c.QueryParam("entity_type"),
+			EntityID:   c.QueryParam("entity_id"),
+		}
+		if s := c.QueryParam("actor_id"); s != "" {
+			actorID, err := uuid.Parse(s)
+			if err != nil {
+				return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+			}
+			filter.ActorID = &actorID
+		}
+		if filter.From, err = parseTime(c.QueryParam("from")); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+		if filter.To, err = parseTime(c.QueryParam("to")); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		entries, err := h.auditUC.GetEntries(c.Request().Context(), filter, paginationQuery)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, entries)
+	}
+}
+
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
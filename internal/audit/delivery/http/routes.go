@@ -0,0 +1,14 @@
+package http
+
+import (
+	"equiptrack/internal/audit"
+	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MapAuditRoutes(auditGroup *echo.Group, h audit.Handlers, mw *middleware.MiddlewareManager) {
+	auditGroup.Use(mw.AuthJWTMiddleware, mw.IsAdminMiddleware, mw.RequireScope(models.ScopeUsersRead))
+	auditGroup.GET("", h.GetEntries())
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"equiptrack/internal/models"
+)
+
+// Fields never stored in the audit trail
+var redacted = map[string]bool{
+	"password": true,
+	"secret":   true,
+	"token":    true,
+	"key":      true,
+}
+
+// Diff returns the changed top level JSON fields of before and after, either may be nil
+// for created or deleted entities. Nil is returned when nothing changed
+func Diff(before interface{}, after interface{}) (json.RawMessage, error) {
+	b, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]models.AuditChange)
+	for field, value := range b {
+		if !reflect.DeepEqual(value, a[field]) {
+			changes[field] = models.AuditChange{Before: value, After: a[field]}
+		}
+	}
+	for field, value := range a {
+		if _, ok := b[field]; !ok {
+			changes[field] = models.AuditChange{After: value}
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+func toFields(v interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return fields, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(raw, &fields); err != nil {
+		// scalars and lists are kept as a single value
+		var value interface{}
+		if err = json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"value": value}, nil
+	}
+	for field := range fields {
+		if redacted[field] {
+			delete(fields, field)
+		}
+	}
+	return fields, nil
+}
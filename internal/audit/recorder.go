@@ -0,0 +1,10 @@
+package audit
+
+import "context"
+
+// Records a mutating operation in the audit trail. The actor and request metadata are
+// taken from the context, before and after are diffed field by field. Recording never
+// fails the caller
+type Recorder interface {
+	Record(ctx context.Context, action string, entityType string, entityID string, before interface{}, after interface{})
+}
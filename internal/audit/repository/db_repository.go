@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/internal/audit"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+
+	"github.com/pkg/errors"
+)
+
+type auditRepo struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) audit.Repository {
+	return &auditRepo{db: db}
+}
+
+func (r *auditRepo) Create(ctx context.Context, entry *models.AuditEntry) error {
+	var changes interface{}
+	if len(entry.Changes) > 0 {
+		changes = []byte(entry.Changes)
+	}
+	if err := r.db.QueryRowContext(
+		ctx,
+		qCreateEntry,
+		entry.ActorID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		changes,
+		entry.RequestID,
+		entry.IPAddress,
+	).Scan(&entry.AuditID, &entry.CreatedAt); err != nil {
+		return errors.Wrap(err, "auditRepo.Create.QueryRowContext")
+	}
+	return nil
+}
+
+func (r *auditRepo) GetEntries(
+	ctx context.Context,
+	filter *models.AuditFilter,
+	pq *utils.PaginationQuery,
+) (*models.AuditList, error) {
+	args := []interface{}{filter.ActorID, filter.Action, filter.EntityType, filter.EntityID, filter.From, filter.To}
+
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, qGetTotal, args...).Scan(&totalCount); err != nil {
+		return nil, errors.Wrap(err, "auditRepo.GetEntries.totalCount")
+	}
+
+	var entries = make([]models.AuditEntry, 0, pq.GetSize())
+	if totalCount > 0 {
+		rows, err := r.db.QueryContext(ctx, qGetEntries, append(args, pq.GetOffset(), pq.GetLimit())...)
+		if err != nil {
+			return nil, errors.Wrap(err, "auditRepo.GetEntries.QueryContext")
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var e models.AuditEntry
+			var changes []byte
+			if err := rows.Scan(
+				&e.AuditID,
+				&e.ActorID,
+				&e.Action,
+				&e.EntityType,
+				&e.EntityID,
+				&changes,
+				&e.RequestID,
+				&e.IPAddress,
+				&e.CreatedAt,
+			); err != nil {
+				return nil, errors.Wrap(err, "auditRepo.GetEntries.QueryContext.ScanRows")
+			}
+			e.Changes = changes
+			entries = append(entries, e)
+		}
+	}
+
+	return &models.AuditList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Entries:    entries,
+	}, nil
+}
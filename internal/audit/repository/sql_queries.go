@@ -0,0 +1,21 @@
+package repository
+
+const (
+	qCreateEntry = `INSERT INTO audit_log (actor_id, action, entity_type, entity_id, changes, request_id, ip_address)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING audit_id, created_at`
+
+	// $1..$6 filter by actor, action, entity type, entity id and the created_at range, NULL or '' match all
+	qAuditWhere = `WHERE ($1::uuid IS NULL OR actor_id = $1)
+	AND ($2 = '' OR action = $2)
+	AND ($3 = '' OR entity_type = $3)
+	AND ($4 = '' OR entity_id = $4)
+	AND ($5::timestamptz IS NULL OR created_at >= $5)
+	AND ($6::timestamptz IS NULL OR created_at < $6)`
+	qGetTotal   = `SELECT COUNT(audit_id) FROM audit_log ` + qAuditWhere
+	qGetEntries = `SELECT audit_id, actor_id, action, entity_type, entity_id, changes, request_id, ip_address, created_at
+	FROM audit_log ` + qAuditWhere + `
+	ORDER BY audit_id DESC
+	OFFSET $7
+	LIMIT $8`
+)
@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+)
+
+type UseCase interface {
+	Recorder
+	GetEntries(ctx context.Context, filter *models.AuditFilter, pq *utils.PaginationQuery) (*models.AuditList, error)
+}
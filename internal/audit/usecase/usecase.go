@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"equiptrack/config"
+	"equiptrack/internal/audit"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+type auditUC struct {
+	cfg       *config.Config
+	auditRepo audit.Repository
+	logger    *logrus.Logger
+}
+
+func NewAuditUseCase(cfg *config.Config, auditRepo audit.Repository, log *logrus.Logger) audit.UseCase {
+	return &auditUC{cfg: cfg, auditRepo: auditRepo, logger: log}
+}
+
+func (u *auditUC) Record(
+	ctx context.Context,
+	action string,
+	entityType string,
+	entityID string,
+	before interface{},
+	after interface{},
+) {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		u.logger.Errorf("auditUC.Record.Diff: %v", err)
+	}
+
+	meta := utils.GetRequestMetaFromCtx(ctx)
+	entry := &models.AuditEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		RequestID:  meta.RequestID,
+		IPAddress:  meta.IPAddress,
+	}
+	// Operations outside of an authenticated request, like registration or scheduled jobs, have no actor
+	if user, err := utils.GetUserFromCtx(ctx); err == nil {
+		entry.ActorID = &user.UserID
+	}
+
+	if err = u.auditRepo.Create(ctx, entry); err != nil {
+		u.logger.Errorf("auditUC.Record.Create: action %s %s %s: %v", action, entityType, entityID, err)
+	}
+}
+
+func (u *auditUC) GetEntries(
+	ctx context.Context,
+	filter *models.AuditFilter,
+	pq *utils.PaginationQuery,
+) (*models.AuditList, error) {
+	return u.auditRepo.GetEntries(ctx, filter, pq)
+}
@@ -0,0 +1,12 @@
+package auth
+
+import (
+	"context"
+	"equiptrack/internal/models"
+)
+
+// Password login backend
+type Authenticator interface {
+	Name() string
+	Authenticate(ctx context.Context, login string, password string) (*models.Identity, error)
+}
@@ -0,0 +1,33 @@
+package authenticator
+
+import (
+	"equiptrack/config"
+	"equiptrack/internal/auth"
+	"fmt"
+)
+
+const (
+	LocalBackend = "local"
+	LDAPBackend  = "ldap"
+)
+
+// Build authenticators for the configured backends, local DB is used when none are set
+func NewAuthenticators(cfg *config.Config, authRepo auth.Repository) ([]auth.Authenticator, error) {
+	backends := cfg.Auth.Backends
+	if len(backends) == 0 {
+		backends = []string{LocalBackend}
+	}
+
+	authenticators := make([]auth.Authenticator, 0, len(backends))
+	for _, backend := range backends {
+		switch backend {
+		case LocalBackend:
+			authenticators = append(authenticators, NewLocalAuthenticator(authRepo))
+		case LDAPBackend:
+			authenticators = append(authenticators, NewLDAPAuthenticator(&cfg.Auth.LDAP))
+		default:
+			return nil, fmt.Errorf("unknown auth backend %q", backend)
+		}
+	}
+	return authenticators, nil
+}
@@ -0,0 +1,159 @@
+package authenticator
+
+import (
+	"context"
+	"crypto/tls"
+	"equiptrack/config"
+	"equiptrack/internal/auth"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"fmt"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+)
+
+const defaultLDAPTimeout = 5
+
+type ldapAuthenticator struct {
+	cfg *config.LDAPConfig
+}
+
+// Authenticator doing LDAP simple bind as the user found by login
+func NewLDAPAuthenticator(cfg *config.LDAPConfig) auth.Authenticator {
+	return &ldapAuthenticator{cfg: cfg}
+}
+
+func (a *ldapAuthenticator) Name() string {
+	return LDAPBackend
+}
+
+func (a *ldapAuthenticator) Authenticate(ctx context.Context, login string, password string) (*models.Identity, error) {
+	// empty password would be an unauthenticated bind which always succeeds
+	if login == "" || password == "" {
+		return nil, httpErrors.NewUnauthorizedError(httpErrors.WrongCredentials)
+	}
+
+	conn, err := a.dial()
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	defer conn.Close()
+
+	if err = a.bindService(conn); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	entry, err := a.findUser(conn, login)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, httpErrors.NewUnauthorizedError(httpErrors.WrongCredentials)
+		}
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "ldapAuthenticator.Authenticate.Bind"))
+	}
+
+	if err = a.bindService(conn); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	groups, err := a.findGroups(conn, entry)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	return &models.Identity{
+		Provider: LDAPBackend,
+		Subject:  entry.GetAttributeValue(a.cfg.LoginAttribute),
+		Login:    entry.GetAttributeValue(a.cfg.LoginAttribute),
+		Email:    entry.GetAttributeValue(a.cfg.EmailAttribute),
+		Role:     utils.MapGroupsToRole(a.cfg.RoleMappings, groups, a.cfg.DefaultRole),
+	}, nil
+}
+
+func (a *ldapAuthenticator) dial() (*ldap.Conn, error) {
+	timeout := a.cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultLDAPTimeout
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: a.cfg.InsecureSkipVerify}
+
+	conn, err := ldap.DialURL(a.cfg.URL, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, errors.Wrap(err, "ldapAuthenticator.dial.DialURL")
+	}
+	conn.SetTimeout(time.Second * timeout)
+
+	if a.cfg.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "ldapAuthenticator.dial.StartTLS")
+		}
+	}
+	return conn, nil
+}
+
+func (a *ldapAuthenticator) bindService(conn *ldap.Conn) error {
+	if a.cfg.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(a.cfg.BindDN, a.cfg.BindPassword); err != nil {
+		return errors.Wrap(err, "ldapAuthenticator.bindService.Bind")
+	}
+	return nil
+}
+
+func (a *ldapAuthenticator) findUser(conn *ldap.Conn, login string) (*ldap.Entry, error) {
+	request := ldap.NewSearchRequest(
+		a.cfg.UserBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(a.cfg.UserFilter, ldap.EscapeFilter(login)),
+		[]string{"dn", a.cfg.LoginAttribute, a.cfg.EmailAttribute, "memberOf"},
+		nil,
+	)
+	result, err := conn.Search(request)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "ldapAuthenticator.findUser.Search"))
+	}
+	if len(result.Entries) != 1 {
+		return nil, httpErrors.NewUnauthorizedError(httpErrors.WrongCredentials)
+	}
+	return result.Entries[0], nil
+}
+
+// Group names come from a group search when GroupBaseDN is set, otherwise from memberOf
+func (a *ldapAuthenticator) findGroups(conn *ldap.Conn, user *ldap.Entry) ([]string, error) {
+	if a.cfg.GroupBaseDN == "" {
+		groups := make([]string, 0)
+		for _, groupDN := range user.GetAttributeValues("memberOf") {
+			dn, err := ldap.ParseDN(groupDN)
+			if err != nil || len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) == 0 {
+				continue
+			}
+			groups = append(groups, dn.RDNs[0].Attributes[0].Value)
+		}
+		return groups, nil
+	}
+
+	request := ldap.NewSearchRequest(
+		a.cfg.GroupBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(a.cfg.GroupFilter, ldap.EscapeFilter(user.DN)),
+		[]string{a.cfg.GroupNameAttribute},
+		nil,
+	)
+	result, err := conn.Search(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "ldapAuthenticator.findGroups.Search")
+	}
+
+	groups := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.GetAttributeValue(a.cfg.GroupNameAttribute))
+	}
+	return groups, nil
+}
@@ -0,0 +1,40 @@
+package authenticator
+
+import (
+	"context"
+	"equiptrack/internal/auth"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+
+	"github.com/pkg/errors"
+)
+
+type localAuthenticator struct {
+	authRepo auth.Repository
+}
+
+// Authenticator checking bcrypt hashes in the users table
+func NewLocalAuthenticator(authRepo auth.Repository) auth.Authenticator {
+	return &localAuthenticator{authRepo: authRepo}
+}
+
+func (a *localAuthenticator) Name() string {
+	return LocalBackend
+}
+
+func (a *localAuthenticator) Authenticate(ctx context.Context, login string, password string) (*models.Identity, error) {
+	foundUser, err := a.authRepo.FindByLogin(ctx, &models.User{Login: login})
+	if err != nil {
+		return nil, err
+	}
+	if err = foundUser.ComparePasswords(password); err != nil {
+		return nil, httpErrors.NewUnauthorizedError(errors.Wrap(err, "localAuthenticator.Authenticate.ComparePasswords"))
+	}
+
+	return &models.Identity{
+		Provider: models.LocalIdentityProvider,
+		Subject:  foundUser.UserID.String(),
+		Login:    foundUser.Login,
+		Email:    foundUser.Email,
+	}, nil
+}
@@ -10,9 +10,18 @@ import (
 
 type Repository interface {
 	Register(ctx context.Context, user *models.User) (*models.User, error)
+	Update(ctx context.Context, user *models.User) (*models.User, error)
 	Delete(ctx context.Context, userID uuid.UUID) error
 	GetByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
 	FindByLogin(ctx context.Context, user *models.User) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	IsEmailTaken(ctx context.Context, email string, exceptID uuid.UUID) (bool, error)
+	FindByIdentity(ctx context.Context, provider string, subject string) (*models.User, error)
+	LinkIdentity(ctx context.Context, userID uuid.UUID, identity *models.Identity) error
+	UpdateRole(ctx context.Context, userID uuid.UUID, role string) error
+
+	CreateEmailVerification(ctx context.Context, verification *models.EmailVerification) error
+	VerifyEmail(ctx context.Context, tokenHash string) (uuid.UUID, error)
 
 	SetSession(ctx context.Context, userWithToken *models.UserWithToken) error
 	GetSession(ctx context.Context, userID uuid.UUID, token string) (*models.Session, error)
@@ -6,10 +6,17 @@ type Handlers interface {
 	Register() echo.HandlerFunc
 	Login() echo.HandlerFunc
 	Delete() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	GetMe() echo.HandlerFunc
+	UpdateMe() echo.HandlerFunc
 	RefreshJWT() echo.HandlerFunc
 	Logout() echo.HandlerFunc
 	GetUserByID() echo.HandlerFunc
 	CheckAuthorized() echo.HandlerFunc
+	VerifyEmail() echo.HandlerFunc
+	ResendEmailVerification() echo.HandlerFunc
+	OIDCLogin() echo.HandlerFunc
+	OIDCCallback() echo.HandlerFunc
 
 	GetUsers() echo.HandlerFunc
 }
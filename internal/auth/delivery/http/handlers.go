@@ -7,27 +7,36 @@ import (
 	"equiptrack/internal/models"
 	"equiptrack/internal/utils"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcCookieName   = "oidc_flow"
+	oidcCookiePath   = "/api/auth/oidc"
+	oidcCookieMaxAge = 10 * 60
 )
 
 type authHandlers struct {
 	cfg    *config.Config
 	authUC auth.UseCase
+	oidc   auth.OIDCProvider
 	logger *logrus.Logger
 }
 
 // NewAuthHandlers Auth handlers constructor
-func NewAuthHandlers(cfg *config.Config, authUC auth.UseCase, log *logrus.Logger) auth.Handlers {
-	return &authHandlers{cfg: cfg, authUC: authUC, logger: log}
+func NewAuthHandlers(cfg *config.Config, authUC auth.UseCase, oidc auth.OIDCProvider, log *logrus.Logger) auth.Handlers {
+	return &authHandlers{cfg: cfg, authUC: authUC, oidc: oidc, logger: log}
 }
 
 func (h *authHandlers) Register() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		user := &models.User{}
-		if err := c.Bind(user); err != nil {
+		if err := utils.ReadRequest(c, user); err != nil {
 			utils.LogResponseError(c, h.logger, err)
 			return c.JSON(httpErrors.ErrorResponse(err))
 		}
@@ -44,6 +53,7 @@ func (h *authHandlers) Register() echo.HandlerFunc {
 
 func (h *authHandlers) Login() echo.HandlerFunc {
 	type Login struct {
+		// login or verified email
 		Login    string `json:"login" db:"login" validate:"omitempty"`
 		Password string `json:"password,omitempty" db:"password" validate:"required"`
 	}
@@ -104,6 +114,113 @@ func (h *authHandlers) GetUsers() echo.HandlerFunc {
 	}
 }
 
+func (h *authHandlers) GetMe() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		u, err := utils.GetUserFromCtx(c.Request().Context())
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.Unauthorized)
+		}
+
+		return c.JSON(http.StatusOK, u)
+	}
+}
+
+// Profile fields of an update, fields left out keep their stored value and an empty
+// string clears one
+type profileUpdate struct {
+	Email      *string `json:"email" validate:"omitempty,email,lte=100"`
+	FullName   *string `json:"full_name" validate:"omitempty,lte=100"`
+	Phone      *string `json:"phone" validate:"omitempty,lte=30"`
+	Department *string `json:"department" validate:"omitempty,lte=100"`
+}
+
+func (p *profileUpdate) apply(user *models.User) {
+	if p.Email != nil {
+		user.Email = *p.Email
+	}
+	if p.FullName != nil {
+		user.FullName = *p.FullName
+	}
+	if p.Phone != nil {
+		user.Phone = *p.Phone
+	}
+	if p.Department != nil {
+		user.Department = *p.Department
+	}
+}
+
+// Self-service profile update, login and role can only be changed by admins
+func (h *authHandlers) UpdateMe() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		u, err := utils.GetUserFromCtx(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.Unauthorized)
+		}
+
+		profile := &profileUpdate{}
+		if err := utils.ReadRequest(c, profile); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		user := &models.User{
+			UserID:     u.UserID,
+			Email:      u.Email,
+			FullName:   u.FullName,
+			Phone:      u.Phone,
+			Department: u.Department,
+		}
+		profile.apply(user)
+		updatedUser, err := h.authUC.Update(ctx, user)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, updatedUser)
+	}
+}
+
+// Admin update of any user, fields left out keep their stored value
+func (h *authHandlers) Update() echo.HandlerFunc {
+	type Update struct {
+		profileUpdate
+		Login string `json:"login" validate:"omitempty,lte=50"`
+		Role  string `json:"role" validate:"omitempty,lte=20"`
+	}
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		uID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		update := &Update{}
+		if err := utils.ReadRequest(c, update); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		user, err := h.authUC.GetByID(ctx, uID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		// login and role are kept by the update when empty
+		user.Login = update.Login
+		user.Role = update.Role
+		update.apply(user)
+
+		updatedUser, err := h.authUC.Update(ctx, user)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, updatedUser)
+	}
+}
+
 func (h *authHandlers) Delete() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		uID, err := uuid.Parse(c.Param("user_id"))
@@ -172,8 +289,104 @@ func (h *authHandlers) Logout() echo.HandlerFunc {
 	}
 }
 
+// Target of the link sent in verification mail
+func (h *authHandlers) VerifyEmail() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.QueryParam("token")
+		if token == "" {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err := h.authUC.VerifyEmail(c.Request().Context(), token); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *authHandlers) ResendEmailVerification() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		u, err := utils.GetUserFromCtx(ctx)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.Unauthorized)
+		}
+
+		if err = h.authUC.ResendEmailVerification(ctx, u.UserID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
 func (h *authHandlers) CheckAuthorized() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		return c.NoContent(http.StatusOK)
 	}
 }
+
+// Redirects to the identity provider, flow state and PKCE verifier are kept in a short-lived cookie
+func (h *authHandlers) OIDCLogin() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !h.cfg.OIDC.Enabled {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewNotFoundError("oidc login is disabled"))
+		}
+
+		state, nonce, verifier := oauth2.GenerateVerifier(), oauth2.GenerateVerifier(), oauth2.GenerateVerifier()
+		url, err := h.oidc.AuthCodeURL(c.Request().Context(), state, nonce, verifier)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewInternalServerError(err))
+		}
+
+		c.SetCookie(&http.Cookie{
+			Name:     oidcCookieName,
+			Value:    strings.Join([]string{state, nonce, verifier}, "."),
+			Path:     oidcCookiePath,
+			MaxAge:   oidcCookieMaxAge,
+			HttpOnly: true,
+			Secure:   c.Scheme() == "https",
+			SameSite: http.SameSiteLaxMode,
+		})
+		return c.Redirect(http.StatusFound, url)
+	}
+}
+
+func (h *authHandlers) OIDCCallback() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !h.cfg.OIDC.Enabled {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewNotFoundError("oidc login is disabled"))
+		}
+
+		cookie, err := c.Cookie(oidcCookieName)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewUnauthorizedError(httpErrors.NoCookie))
+		}
+		c.SetCookie(&http.Cookie{Name: oidcCookieName, Path: oidcCookiePath, MaxAge: -1})
+
+		flow := strings.Split(cookie.Value, ".")
+		if len(flow) != 3 || c.QueryParam("state") != flow[0] {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewUnauthorizedError("oidc state mismatch"))
+		}
+		if idpErr := c.QueryParam("error"); idpErr != "" {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewUnauthorizedError(idpErr))
+		}
+
+		ctx := c.Request().Context()
+		identity, err := h.oidc.Exchange(ctx, c.QueryParam("code"), flow[2], flow[1])
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewUnauthorizedError(err))
+		}
+
+		userWithToken, err := h.authUC.LoginWithIdentity(ctx, identity)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, userWithToken)
+	}
+}
@@ -0,0 +1,353 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"equiptrack/config"
+	"equiptrack/internal/auth"
+	"equiptrack/internal/auth/oidc"
+	"equiptrack/internal/models"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	mockClientID     = "equiptrack"
+	mockClientSecret = "secret"
+	mockCode         = "auth-code"
+	mockKeyID        = "test-key"
+)
+
+// Local identity provider serving discovery, JWKS and the token endpoint. It remembers the
+// nonce and PKCE challenge of the last authorization request like a real provider would
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu        sync.Mutex
+	nonce     string
+	challenge string
+	// claims merged over the defaults of the issued id_token
+	claims jwt.MapClaims
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": mockKeyID,
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	clientID, secret, ok := r.BasicAuth()
+	if !ok {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != mockClientID || secret != mockClientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		writeJSON(w, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("code") != mockCode || base64.RawURLEncoding.EncodeToString(sum[:]) != idp.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                idp.server.URL,
+		"aud":                mockClientID,
+		"sub":                "subject-1",
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Hour).Unix(),
+		"nonce":              idp.nonce,
+		"preferred_username": "jdoe",
+		"email":              "jdoe@example.com",
+		"email_verified":     true,
+		"groups":             []string{"staff", "equipment-admins"},
+	}
+	for k, v := range idp.claims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = mockKeyID
+	idToken, err := token.SignedString(idp.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// Records what the browser would send to the authorization endpoint
+func (idp *mockIdP) authorize(t *testing.T, location string) url.Values {
+	t.Helper()
+	u, err := url.Parse(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Scheme + "://" + u.Host + u.Path; got != idp.server.URL+"/authorize" {
+		t.Fatalf("redirected to %s, want the authorization endpoint", got)
+	}
+	query := u.Query()
+	idp.mu.Lock()
+	idp.nonce, idp.challenge = query.Get("nonce"), query.Get("code_challenge")
+	idp.mu.Unlock()
+	return query
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// Use case double recording the identity the callback logs in with
+type identityUC struct {
+	auth.UseCase
+	identity *models.Identity
+}
+
+func (u *identityUC) LoginWithIdentity(ctx context.Context, identity *models.Identity) (*models.UserWithToken, error) {
+	u.identity = identity
+	return &models.UserWithToken{User: &models.User{Login: identity.Login, Role: identity.Role}, AccessToken: "token"}, nil
+}
+
+func newOIDCHandlers(idp *mockIdP) (*authHandlers, *identityUC) {
+	cfg := &config.Config{OIDC: config.OIDCConfig{
+		Enabled:      true,
+		IssuerURL:    idp.server.URL,
+		ClientID:     mockClientID,
+		ClientSecret: mockClientSecret,
+		RedirectURL:  "http://localhost/api/auth/oidc/callback",
+		Scopes:       []string{"email", "profile"},
+		GroupsClaim:  "groups",
+		DefaultRole:  "user",
+		RoleMappings: []config.RoleMapping{{Group: "equipment-admins", Role: "admin"}},
+	}}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	uc := &identityUC{}
+	return &authHandlers{cfg: cfg, authUC: uc, oidc: oidc.NewOIDCProvider(&cfg.OIDC), logger: logger}, uc
+}
+
+// Runs the login redirect, returning the flow cookie and the authorization request
+func startLogin(t *testing.T, h *authHandlers, idp *mockIdP) (*http.Cookie, url.Values) {
+	t.Helper()
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil), rec)
+	if err := h.OIDCLogin()(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusFound {
+		t.Fatalf("login status %d, want %d: %s", rec.Code, http.StatusFound, rec.Body.String())
+	}
+	query := idp.authorize(t, rec.Header().Get(echo.HeaderLocation))
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == oidcCookieName {
+			return cookie, query
+		}
+	}
+	t.Fatal("login set no flow cookie")
+	return nil, nil
+}
+
+func callback(t *testing.T, h *authHandlers, cookie *http.Cookie, query url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/callback?"+query.Encode(), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	if err := h.OIDCCallback()(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestOIDCLoginRedirect(t *testing.T) {
+	idp := newMockIdP(t)
+	h, _ := newOIDCHandlers(idp)
+
+	cookie, query := startLogin(t, h, idp)
+	for param, want := range map[string]string{
+		"client_id":             mockClientID,
+		"response_type":         "code",
+		"redirect_uri":          h.cfg.OIDC.RedirectURL,
+		"code_challenge_method": "S256",
+	} {
+		if got := query.Get(param); got != want {
+			t.Errorf("%s = %q, want %q", param, got, want)
+		}
+	}
+	if !strings.Contains(query.Get("scope"), "openid") {
+		t.Errorf("scope %q lacks openid", query.Get("scope"))
+	}
+	flow := strings.Split(cookie.Value, ".")
+	if len(flow) != 3 || flow[0] != query.Get("state") || flow[1] != query.Get("nonce") {
+		t.Errorf("flow cookie %q doesn't match the authorization request", cookie.Value)
+	}
+	if !cookie.HttpOnly || cookie.Path != oidcCookiePath {
+		t.Errorf("flow cookie must be http only and scoped to %s", oidcCookiePath)
+	}
+}
+
+func TestOIDCLoginDisabled(t *testing.T) {
+	idp := newMockIdP(t)
+	h, _ := newOIDCHandlers(idp)
+	h.cfg.OIDC.Enabled = false
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil), rec)
+	if err := h.OIDCLogin()(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestOIDCCallback(t *testing.T) {
+	tests := []struct {
+		name string
+		// id_token claims overriding the defaults
+		claims jwt.MapClaims
+		// changes the callback request of an otherwise valid flow
+		tamper     func(cookie **http.Cookie, query url.Values)
+		wantStatus int
+		want       *models.Identity
+	}{
+		{
+			name:       "verified email and mapped role",
+			wantStatus: http.StatusOK,
+			want:       &models.Identity{Provider: oidc.ProviderName, Subject: "subject-1", Login: "jdoe", Email: "jdoe@example.com", Role: "admin"},
+		},
+		{
+			name:       "unverified email isn't used",
+			claims:     jwt.MapClaims{"email_verified": false, "groups": []string{"staff"}},
+			wantStatus: http.StatusOK,
+			want:       &models.Identity{Provider: oidc.ProviderName, Subject: "subject-1", Login: "jdoe", Role: "user"},
+		},
+		{
+			name:       "state mismatch",
+			tamper:     func(_ **http.Cookie, query url.Values) { query.Set("state", "forged") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing flow cookie",
+			tamper:     func(cookie **http.Cookie, _ url.Values) { *cookie = nil },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "error from the provider",
+			tamper:     func(_ **http.Cookie, query url.Values) { query.Set("error", "access_denied") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong code",
+			tamper:     func(_ **http.Cookie, query url.Values) { query.Set("code", "stolen") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "nonce mismatch",
+			claims:     jwt.MapClaims{"nonce": "replayed"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "token for another client",
+			claims:     jwt.MapClaims{"aud": "other-client"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "expired token",
+			claims:     jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newMockIdP(t)
+			idp.claims = tt.claims
+			h, uc := newOIDCHandlers(idp)
+
+			cookie, authRequest := startLogin(t, h, idp)
+			query := url.Values{"state": {authRequest.Get("state")}, "code": {mockCode}}
+			if tt.tamper != nil {
+				tt.tamper(&cookie, query)
+			}
+
+			rec := callback(t, h, cookie, query)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.want == nil {
+				if uc.identity != nil {
+					t.Errorf("logged in with %+v, want no login", uc.identity)
+				}
+				return
+			}
+			if uc.identity == nil || *uc.identity != *tt.want {
+				t.Errorf("logged in with %+v, want %+v", uc.identity, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"equiptrack/internal/auth"
 	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
 
 	"github.com/labstack/echo/v4"
 )
@@ -12,10 +13,17 @@ func MapAuthRoutes(authGroup *echo.Group, h auth.Handlers, mw *middleware.Middle
 	authGroup.POST("/login", h.Login())
 	authGroup.POST("/refresh", h.RefreshJWT())
 	authGroup.POST("/logout", h.Logout())
+	authGroup.GET("/oidc/login", h.OIDCLogin())
+	authGroup.GET("/oidc/callback", h.OIDCCallback())
+	authGroup.GET("/verify_email", h.VerifyEmail())
 	authGroup.Use(mw.AuthJWTMiddleware)
-	authGroup.GET("/:user_id", h.GetUserByID())
+	authGroup.GET("/me", h.GetMe(), mw.RequireScope(models.ScopeUsersRead))
+	authGroup.PUT("/me", h.UpdateMe(), mw.RequireScope(models.ScopeUsersWrite))
+	authGroup.POST("/verify_email/resend", h.ResendEmailVerification(), mw.RequireScope(models.ScopeUsersWrite))
+	authGroup.GET("/:user_id", h.GetUserByID(), mw.RequireScope(models.ScopeUsersRead))
 	authGroup.GET("/status", h.CheckAuthorized())
 
-	authGroup.GET("/all", h.GetUsers(), mw.IsAdminMiddleware)
-	authGroup.DELETE("/:user_id", h.Delete(), mw.IsAdminMiddleware)
+	authGroup.GET("/all", h.GetUsers(), mw.IsAdminMiddleware, mw.RequireScope(models.ScopeUsersRead))
+	authGroup.PUT("/:user_id", h.Update(), mw.IsAdminMiddleware, mw.RequireScope(models.ScopeUsersWrite))
+	authGroup.DELETE("/:user_id", h.Delete(), mw.IsAdminMiddleware, mw.RequireScope(models.ScopeUsersWrite))
 }
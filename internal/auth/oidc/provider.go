@@ -0,0 +1,118 @@
+package oidc
+
+import (
+	"context"
+	"equiptrack/config"
+	"equiptrack/internal/auth"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const ProviderName = "oidc"
+
+type oidcProvider struct {
+	cfg *config.OIDCConfig
+
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// OIDC provider constructor, discovery is done lazily on the first login so
+// the api starts even when the identity provider is down
+func NewOIDCProvider(cfg *config.OIDCConfig) auth.OIDCProvider {
+	return &oidcProvider{cfg: cfg}
+}
+
+func (p *oidcProvider) init(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth2 != nil {
+		return nil
+	}
+
+	provider, err := gooidc.NewProvider(ctx, p.cfg.IssuerURL)
+	if err != nil {
+		return errors.Wrap(err, "oidcProvider.init.NewProvider")
+	}
+
+	scopes := append([]string{gooidc.ScopeOpenID}, p.cfg.Scopes...)
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+	return nil
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	if err := p.init(ctx); err != nil {
+		return "", err
+	}
+	return p.oauth2.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code string, verifier string, nonce string) (*models.Identity, error) {
+	if err := p.init(ctx); err != nil {
+		return nil, err
+	}
+
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, errors.Wrap(err, "oidcProvider.Exchange.Exchange")
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("oidcProvider.Exchange: no id_token in token response")
+	}
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "oidcProvider.Exchange.Verify")
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("oidcProvider.Exchange: id_token nonce mismatch")
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, errors.Wrap(err, "oidcProvider.Exchange.Claims")
+	}
+
+	identity := &models.Identity{
+		Provider: ProviderName,
+		Subject:  idToken.Subject,
+		Role:     utils.MapGroupsToRole(p.cfg.RoleMappings, stringList(claims[p.cfg.GroupsClaim]), p.cfg.DefaultRole),
+	}
+	identity.Login, _ = claims["preferred_username"].(string)
+	// unverified email must not be used to link existing accounts
+	if verified, _ := claims["email_verified"].(bool); verified {
+		identity.Email, _ = claims["email"].(string)
+	}
+
+	return identity, nil
+}
+
+func stringList(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
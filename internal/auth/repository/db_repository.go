@@ -22,13 +22,35 @@ func NewAuthRepository(db *sql.DB) auth.Repository {
 
 func (r *authRepo) Register(ctx context.Context, user *models.User) (*models.User, error) {
 	u := &models.User{}
-	if err := r.db.QueryRowContext(ctx, createUserQuery, &user.Login, &user.Password, &user.Role).Scan(&u.UserID); err != nil {
+	if err := r.db.QueryRowContext(ctx, createUserQuery,
+		&user.Login, &user.Password, &user.Role, &user.Email, &user.FullName, &user.Phone, &user.Department, &user.EmailVerified,
+	).Scan(&u.UserID); err != nil {
 		return nil, errors.Wrap(err, "authRepo.Register.StructScan")
 	}
 
 	return u, nil
 }
 
+func (r *authRepo) Update(ctx context.Context, user *models.User) (*models.User, error) {
+	u := &models.User{}
+	if err := r.db.QueryRowContext(ctx, updateUserQuery,
+		&user.Login, &user.Role, &user.Email, &user.FullName, &user.Phone, &user.Department, &user.UserID,
+	).Scan(
+		&u.UserID,
+		&u.Login,
+		&u.Role,
+		&u.Email,
+		&u.FullName,
+		&u.Phone,
+		&u.Department,
+		&u.EmailVerified,
+	); err != nil {
+		return nil, errors.Wrap(err, "authRepo.Update.QueryRowContext")
+	}
+
+	return u, nil
+}
+
 func (r *authRepo) Delete(ctx context.Context, userID uuid.UUID) error {
 	result, err := r.db.ExecContext(ctx, deleteUserQuery, userID)
 	if err != nil {
@@ -52,6 +74,11 @@ func (r *authRepo) GetByID(ctx context.Context, userID uuid.UUID) (*models.User,
 		&user.Login,
 		&user.Password,
 		&user.Role,
+		&user.Email,
+		&user.FullName,
+		&user.Phone,
+		&user.Department,
+		&user.EmailVerified,
 	); err != nil {
 		return nil, errors.Wrap(err, "authRepo.GetByID.QueryRowContext")
 	}
@@ -96,7 +123,7 @@ func (r *authRepo) GetUsers(ctx context.Context, pq *utils.PaginationQuery) (*mo
 	var users = make([]models.User, 0, pq.GetSize())
 	for rows.Next() {
 		var r models.User
-		err := rows.Scan(&r.UserID, &r.Login, &r.Role)
+		err := rows.Scan(&r.UserID, &r.Login, &r.Role, &r.Email, &r.FullName, &r.Phone, &r.Department, &r.EmailVerified)
 		if err != nil {
 			return nil, errors.Wrap(err, "authRepo.GetUsers.QueryContext.ScanRows")
 		}
@@ -120,12 +147,101 @@ func (r *authRepo) FindByLogin(ctx context.Context, user *models.User) (*models.
 		&foundUser.Login,
 		&foundUser.Password,
 		&foundUser.Role,
+		&foundUser.Email,
+		&foundUser.FullName,
+		&foundUser.Phone,
+		&foundUser.Department,
+		&foundUser.EmailVerified,
 	); err != nil {
 		return nil, errors.Wrap(err, "authRepo.FindByLogin.QueryRowContext")
 	}
 	return foundUser, nil
 }
 
+func (r *authRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	foundUser := &models.User{}
+	if err := r.db.QueryRowContext(ctx, findUserByEmail, email).Scan(
+		&foundUser.UserID,
+		&foundUser.Login,
+		&foundUser.Password,
+		&foundUser.Role,
+		&foundUser.Email,
+		&foundUser.FullName,
+		&foundUser.Phone,
+		&foundUser.Department,
+		&foundUser.EmailVerified,
+	); err != nil {
+		return nil, errors.Wrap(err, "authRepo.FindByEmail.QueryRowContext")
+	}
+	return foundUser, nil
+}
+
+// Reports whether a user other than exceptID holds the address as email or login
+func (r *authRepo) IsEmailTaken(ctx context.Context, email string, exceptID uuid.UUID) (bool, error) {
+	var taken bool
+	if err := r.db.QueryRowContext(ctx, isEmailTaken, email, exceptID).Scan(&taken); err != nil {
+		return false, errors.Wrap(err, "authRepo.IsEmailTaken.QueryRowContext")
+	}
+	return taken, nil
+}
+
+func (r *authRepo) FindByIdentity(ctx context.Context, provider string, subject string) (*models.User, error) {
+	foundUser := &models.User{}
+	if err := r.db.QueryRowContext(ctx, findUserByIdentity, provider, subject).Scan(
+		&foundUser.UserID,
+		&foundUser.Login,
+		&foundUser.Password,
+		&foundUser.Role,
+		&foundUser.Email,
+		&foundUser.FullName,
+		&foundUser.Phone,
+		&foundUser.Department,
+		&foundUser.EmailVerified,
+	); err != nil {
+		return nil, errors.Wrap(err, "authRepo.FindByIdentity.QueryRowContext")
+	}
+	return foundUser, nil
+}
+
+func (r *authRepo) LinkIdentity(ctx context.Context, userID uuid.UUID, identity *models.Identity) error {
+	if _, err := r.db.ExecContext(ctx, linkUserIdentity, userID, identity.Provider, identity.Subject); err != nil {
+		return errors.Wrap(err, "authRepo.LinkIdentity.ExecContext")
+	}
+	return nil
+}
+
+func (r *authRepo) UpdateRole(ctx context.Context, userID uuid.UUID, role string) error {
+	result, err := r.db.ExecContext(ctx, updateUserRole, role, userID)
+	if err != nil {
+		return errors.Wrap(err, "authRepo.UpdateRole.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "authRepo.UpdateRole.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "authRepo.UpdateRole.rowsAffected")
+	}
+	return nil
+}
+
+func (r *authRepo) CreateEmailVerification(ctx context.Context, verification *models.EmailVerification) error {
+	if _, err := r.db.ExecContext(ctx, createEmailVerification,
+		verification.TokenHash, verification.UserID, verification.Email, verification.ExpiresAt,
+	); err != nil {
+		return errors.Wrap(err, "authRepo.CreateEmailVerification.ExecContext")
+	}
+	return nil
+}
+
+func (r *authRepo) VerifyEmail(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	if err := r.db.QueryRowContext(ctx, verifyEmail, tokenHash).Scan(&userID); err != nil {
+		return uuid.Nil, errors.Wrap(err, "authRepo.VerifyEmail.QueryRowContext")
+	}
+	return userID, nil
+}
+
 func (r *authRepo) SetSession(ctx context.Context, userWithToken *models.UserWithToken) error {
 	if _, err := r.db.ExecContext(ctx, setUserSession, &userWithToken.User.UserID, &userWithToken.RefreshToken); err != nil {
 		return errors.Wrap(err, "authRepo.SetSession.ExecContext")
@@ -1,10 +1,51 @@
 package repository
 
 const (
-	createUserQuery = `INSERT INTO users (login, password, role) VALUES ($1, $2, $3) RETURNING user_id`
+	createUserQuery = `INSERT INTO users (login, password, role, email, full_name, phone, department, email_verified)
+			VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8) RETURNING user_id`
+	updateUserQuery = `UPDATE users
+			SET login = COALESCE(NULLIF($1, ''), login),
+				role = COALESCE(NULLIF($2, ''), role),
+				email = NULLIF($3, ''),
+				email_verified = email_verified AND email IS NOT DISTINCT FROM NULLIF($3, ''),
+				full_name = $4,
+				phone = $5,
+				department = $6
+			WHERE user_id = $7
+			RETURNING user_id, login, role, COALESCE(email, ''), full_name, phone, department, email_verified`
 	deleteUserQuery = `DELETE FROM users WHERE user_id = $1`
-	getUserQuery    = `SELECT user_id, login, password, role FROM users WHERE user_id = $1`
-	findUserByLogin = `SELECT user_id, login, password, role FROM users WHERE login = $1`
+	getUserQuery    = `SELECT user_id, login, password, role, COALESCE(email, ''), full_name, phone, department, email_verified FROM users WHERE user_id = $1`
+	findUserByLogin = `SELECT user_id, login, password, role, COALESCE(email, ''), full_name, phone, department, email_verified
+			FROM users
+			WHERE login = $1 OR (lower(email) = lower($1) AND email_verified)
+			ORDER BY login = $1 DESC
+			LIMIT 1`
+	findUserByEmail = `SELECT user_id, login, password, role, COALESCE(email, ''), full_name, phone, department, email_verified
+			FROM users
+			WHERE lower(email) = lower($1) AND email_verified`
+	// emails and logins are both accepted at login, so an address is taken by either
+	isEmailTaken = `SELECT EXISTS (
+				SELECT 1 FROM users
+				WHERE (lower(email) = lower($1) OR lower(login) = lower($1)) AND user_id <> $2
+			)`
+	updateUserRole = `UPDATE users SET role = $1 WHERE user_id = $2`
+
+	findUserByIdentity = `SELECT user_id, login, password, role, COALESCE(email, ''), full_name, phone, department, email_verified
+			FROM users
+			INNER JOIN user_identities using(user_id)
+			WHERE provider = $1 AND subject = $2`
+	linkUserIdentity = `INSERT INTO user_identities (user_id, provider, subject) VALUES ($1, $2, $3)`
+
+	createEmailVerification = `INSERT INTO email_verifications (token_hash, user_id, email, expires_at) VALUES ($1, $2, $3, $4)`
+	verifyEmail             = `WITH verification AS (
+				DELETE FROM email_verifications
+				WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+				RETURNING user_id, email
+			)
+			UPDATE users SET email_verified = true
+			FROM verification
+			WHERE users.user_id = verification.user_id AND lower(users.email) = lower(verification.email)
+			RETURNING users.user_id`
 
 	setUserSession       = `INSERT INTO sessions (user_id, refresh_token) VALUES ($1, $2)`
 	getUserSession       = `SELECT id, user_id, refresh_token FROM sessions WHERE user_id = $1 AND refresh_token = $2`
@@ -12,7 +53,7 @@ const (
 	deleteSessionByToken = `DELETE FROM sessions WHERE user_id = $1 AND refresh_token = $2`
 
 	qGetTotal = `SELECT COUNT(user_id) FROM users`
-	qGetUsers = `SELECT user_id, login, role
+	qGetUsers = `SELECT user_id, login, role, COALESCE(email, ''), full_name, phone, department, email_verified
 			FROM users
 			OFFSET $1 
 			LIMIT $2`
@@ -0,0 +1,12 @@
+package auth
+
+import (
+	"context"
+	"equiptrack/internal/models"
+)
+
+// External OpenID Connect identity provider
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error)
+	Exchange(ctx context.Context, code string, verifier string, nonce string) (*models.Identity, error)
+}
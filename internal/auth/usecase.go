@@ -11,10 +11,14 @@ import (
 type UseCase interface {
 	Register(ctx context.Context, user *models.User) (*models.User, error)
 	Login(ctx context.Context, user *models.User) (*models.UserWithToken, error)
+	LoginWithIdentity(ctx context.Context, identity *models.Identity) (*models.UserWithToken, error)
+	Update(ctx context.Context, user *models.User) (*models.User, error)
 	Delete(ctx context.Context, userID uuid.UUID) error
 	GetByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
 	RefreshSession(ctx context.Context, userID uuid.UUID, refreshToken string) (*models.UserWithToken, error)
 	Logout(ctx context.Context, userID uuid.UUID, refreshToken string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendEmailVerification(ctx context.Context, userID uuid.UUID) error
 
 	GetUsers(ctx context.Context, pq *utils.PaginationQuery) (*models.UserList, error)
 }
@@ -2,26 +2,59 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"equiptrack/config"
+	"equiptrack/internal/audit"
 	"equiptrack/internal/auth"
 	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/mailer"
 	"equiptrack/internal/models"
 	"equiptrack/internal/utils"
+	"equiptrack/internal/webhook"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	maxLoginLength = 50
+
+	defaultEmailVerificationTTL = 48
+)
+
 type authUC struct {
-	cfg      *config.Config
-	authRepo auth.Repository
-	logger   *logrus.Logger
+	cfg            *config.Config
+	authRepo       auth.Repository
+	authenticators []auth.Authenticator
+	mailer         mailer.Mailer
+	publisher      webhook.Publisher
+	auditor        audit.Recorder
+	logger         *logrus.Logger
 }
 
-func NewAuthUseCase(cfg *config.Config, authRepo auth.Repository, log *logrus.Logger) auth.UseCase {
-	return &authUC{cfg: cfg, authRepo: authRepo, logger: log}
+func NewAuthUseCase(
+	cfg *config.Config,
+	authRepo auth.Repository,
+	authenticators []auth.Authenticator,
+	mailer mailer.Mailer,
+	publisher webhook.Publisher,
+	auditor audit.Recorder,
+	log *logrus.Logger,
+) auth.UseCase {
+	return &authUC{
+		cfg:            cfg,
+		authRepo:       authRepo,
+		authenticators: authenticators,
+		mailer:         mailer,
+		publisher:      publisher,
+		auditor:        auditor,
+		logger:         log,
+	}
 }
 
 func (u *authUC) Register(ctx context.Context, user *models.User) (*models.User, error) {
@@ -33,6 +66,23 @@ func (u *authUC) Register(ctx context.Context, user *models.User) (*models.User,
 	if err = user.PrepareCreate(); err != nil {
 		return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "authUC.Register.PrepareCreate"))
 	}
+	if err = u.checkEmailDomain(user.Email); err != nil {
+		return nil, err
+	}
+	if err = u.checkEmailFree(ctx, user.Email, uuid.Nil); err != nil {
+		return nil, err
+	}
+	// a login shaped like someone's email would be matched in place of that address at login
+	if strings.Contains(user.Login, "@") {
+		taken, err := u.authRepo.IsEmailTaken(ctx, user.Login, uuid.Nil)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.MsgUserAlreadyExists, nil)
+		}
+	}
+	user.EmailVerified = false
 
 	createdUser, err := u.authRepo.Register(ctx, user)
 	if err != nil {
@@ -40,13 +90,156 @@ func (u *authUC) Register(ctx context.Context, user *models.User) (*models.User,
 	}
 	createdUser.SanitizePassword()
 
+	if user.Email != "" {
+		if err = u.sendEmailVerification(ctx, createdUser.UserID, user.Email); err != nil {
+			u.logger.Errorf("authUC.Register.sendEmailVerification: %v", err)
+		}
+	}
+
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityUser, createdUser.UserID.String(), nil, createdUser)
+	u.publisher.Publish(ctx, models.EventUserRegistered, createdUser)
 	return createdUser, nil
 }
 
+func (u *authUC) Update(ctx context.Context, user *models.User) (*models.User, error) {
+	if err := user.PrepareUpdate(); err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "authUC.Update.PrepareUpdate"))
+	}
+
+	prevUser, err := u.authRepo.GetByID(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	emailChanged := user.Email != prevUser.Email
+	if emailChanged {
+		if err = u.checkEmailDomain(user.Email); err != nil {
+			return nil, err
+		}
+		if err = u.checkEmailFree(ctx, user.Email, user.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedUser, err := u.authRepo.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	updatedUser.SanitizePassword()
+
+	if emailChanged && updatedUser.Email != "" {
+		if err = u.sendEmailVerification(ctx, updatedUser.UserID, updatedUser.Email); err != nil {
+			u.logger.Errorf("authUC.Update.sendEmailVerification: %v", err)
+		}
+	}
+
+	prevUser.SanitizePassword()
+	u.auditor.Record(ctx, models.AuditUpdate, models.AuditEntityUser, updatedUser.UserID.String(), prevUser, updatedUser)
+	u.publisher.Publish(ctx, models.EventUserUpdated, updatedUser)
+	return updatedUser, nil
+}
+
+func (u *authUC) VerifyEmail(ctx context.Context, token string) error {
+	if _, err := u.authRepo.VerifyEmail(ctx, utils.HashToken(token)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return httpErrors.NewBadRequestError(errors.Wrap(err, "authUC.VerifyEmail: invalid or expired verification"))
+		}
+		return err
+	}
+	return nil
+}
+
+func (u *authUC) ResendEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.authRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrEmailRequired, nil)
+	}
+	if user.EmailVerified {
+		return httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrEmailVerified, nil)
+	}
+
+	return u.sendEmailVerification(ctx, user.UserID, user.Email)
+}
+
+// Registration is limited to configured email domains, when there are any
+func (u *authUC) checkEmailDomain(email string) error {
+	domains := u.cfg.Auth.AllowedEmailDomains
+	if len(domains) == 0 {
+		return nil
+	}
+	if email == "" {
+		return httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrEmailRequired, nil)
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return httpErrors.NewBadRequestError("invalid email")
+	}
+	domain := email[at+1:]
+	for _, allowed := range domains {
+		if strings.EqualFold(allowed, domain) {
+			return nil
+		}
+	}
+	return httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrEmailNotAllowed, nil)
+}
+
+// An email belongs to one user, it mustn't be another user's email or login either
+func (u *authUC) checkEmailFree(ctx context.Context, email string, userID uuid.UUID) error {
+	if email == "" {
+		return nil
+	}
+	taken, err := u.authRepo.IsEmailTaken(ctx, email, userID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return httpErrors.NewRestErrorWithMessage(http.StatusBadRequest, httpErrors.ErrEmailAlreadyExists, nil)
+	}
+	return nil
+}
+
+func (u *authUC) sendEmailVerification(ctx context.Context, userID uuid.UUID, email string) error {
+	token, err := utils.NewSecureToken()
+	if err != nil {
+		return errors.Wrap(err, "authUC.sendEmailVerification.NewSecureToken")
+	}
+
+	ttl := u.cfg.Auth.EmailVerificationTTL
+	if ttl == 0 {
+		ttl = defaultEmailVerificationTTL
+	}
+	if err = u.authRepo.CreateEmailVerification(ctx, &models.EmailVerification{
+		TokenHash: utils.HashToken(token),
+		UserID:    userID,
+		Email:     email,
+		ExpiresAt: time.Now().Add(time.Hour * ttl),
+	}); err != nil {
+		return err
+	}
+
+	return u.mailer.Send(ctx, &mailer.Message{
+		To:      email,
+		Subject: "Confirm your EquipTrack email",
+		Body: fmt.Sprintf(
+			"Open the link below to confirm your email address:\n\n%s/api/auth/verify_email?token=%s\n",
+			u.cfg.Server.PublicURL, token,
+		),
+	})
+}
+
 func (u *authUC) Delete(ctx context.Context, userID uuid.UUID) error {
-	if err := u.authRepo.Delete(ctx, userID); err != nil {
+	before, err := u.authRepo.GetByID(ctx, userID)
+	if err != nil {
 		return err
 	}
+	if err = u.authRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+	before.SanitizePassword()
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityUser, userID.String(), before, nil)
 	return nil
 }
 
@@ -64,35 +257,126 @@ func (u *authUC) GetUsers(ctx context.Context, pq *utils.PaginationQuery) (*mode
 	return u.authRepo.GetUsers(ctx, pq)
 }
 
+// Try configured authenticators in order, the first one accepting the credentials wins
 func (u *authUC) Login(ctx context.Context, user *models.User) (*models.UserWithToken, error) {
-	foundUser, err := u.authRepo.FindByLogin(ctx, user)
+	var err error = httpErrors.NewUnauthorizedError(httpErrors.WrongCredentials)
+	for _, authenticator := range u.authenticators {
+		identity, authErr := authenticator.Authenticate(ctx, user.Login, user.Password)
+		if authErr != nil {
+			u.logger.Debugf("authUC.Login: %s backend rejected %s: %v", authenticator.Name(), user.Login, authErr)
+			err = authErr
+			continue
+		}
+		return u.LoginWithIdentity(ctx, identity)
+	}
+	return nil, err
+}
+
+func (u *authUC) LoginWithIdentity(ctx context.Context, identity *models.Identity) (*models.UserWithToken, error) {
+	user, err := u.provisionIdentity(ctx, identity)
 	if err != nil {
 		return nil, err
 	}
-	if err = foundUser.ComparePasswords(user.Password); err != nil {
-		return nil, httpErrors.NewUnauthorizedError(errors.Wrap(err, "authUC.Login.ComparePasswords"))
+
+	if identity.Role != "" && identity.Role != user.Role {
+		if err = u.authRepo.UpdateRole(ctx, user.UserID, identity.Role); err != nil {
+			return nil, err
+		}
+		user.Role = identity.Role
+	}
+
+	user.SanitizePassword()
+
+	return u.generateTokens(ctx, user)
+}
+
+// Find user linked to the external identity, link existing user by email or create a new one
+func (u *authUC) provisionIdentity(ctx context.Context, identity *models.Identity) (*models.User, error) {
+	if identity.Provider == models.LocalIdentityProvider {
+		userID, err := uuid.Parse(identity.Subject)
+		if err != nil {
+			return nil, err
+		}
+		return u.authRepo.GetByID(ctx, userID)
+	}
+
+	user, err := u.authRepo.FindByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
 	}
 
-	foundUser.SanitizePassword()
+	if identity.Email != "" {
+		user, err = u.authRepo.FindByEmail(ctx, identity.Email)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
 
-	accessToken, err := utils.GenerateJWTToken(foundUser, u.cfg)
+	if user == nil {
+		password, err := utils.NewRefreshToken()
+		if err != nil {
+			return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "authUC.provisionIdentity.NewRefreshToken"))
+		}
+		// emails coming from the identity provider are trusted as verified
+		user = &models.User{
+			Login:         u.freeLogin(ctx, identity),
+			Password:      password,
+			Role:          identity.Role,
+			Email:         identity.Email,
+			EmailVerified: identity.Email != "",
+		}
+		if err = user.PrepareCreate(); err != nil {
+			return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "authUC.provisionIdentity.PrepareCreate"))
+		}
+		createdUser, err := u.authRepo.Register(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		user.UserID = createdUser.UserID
+	}
+
+	if err = u.authRepo.LinkIdentity(ctx, user.UserID, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Pick login for provisioned user which is not taken by someone else
+func (u *authUC) freeLogin(ctx context.Context, identity *models.Identity) string {
+	candidates := []string{identity.Login, identity.Email, identity.Provider + "-" + identity.Subject}
+	for _, login := range candidates {
+		if login == "" || len(login) > maxLoginLength {
+			continue
+		}
+		if _, err := u.authRepo.FindByLogin(ctx, &models.User{Login: login}); err != nil {
+			return login
+		}
+	}
+	return uuid.NewString()
+}
+
+func (u *authUC) generateTokens(ctx context.Context, user *models.User) (*models.UserWithToken, error) {
+	accessToken, err := utils.GenerateJWTToken(user, u.cfg)
 	if err != nil {
-		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "authUC.Login.GenerateJWTToken"))
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "authUC.generateTokens.GenerateJWTToken"))
 	}
 
 	refreshToken, err := utils.NewRefreshToken()
 	if err != nil {
-		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "authUC.Login.NewRefreshToken"))
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "authUC.generateTokens.NewRefreshToken"))
 	}
 	userWithToken := &models.UserWithToken{
-		User:         foundUser,
+		User:         user,
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}
 
 	err = u.authRepo.SetSession(ctx, userWithToken)
 	if err != nil {
-		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "authUC.Login.SetSession"))
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "authUC.generateTokens.SetSession"))
 	}
 
 	return userWithToken, nil
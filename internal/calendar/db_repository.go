@@ -0,0 +1,19 @@
+package calendar
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	CreateFeed(ctx context.Context, feed *models.CalendarFeed) (*models.CalendarFeed, error)
+	GetFeeds(ctx context.Context, userID uuid.UUID) ([]models.CalendarFeed, error)
+	GetFeedByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeed, error)
+	RevokeFeed(ctx context.Context, userID uuid.UUID, feedID uuid.UUID) error
+
+	GetUserEvents(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.CalendarEvent, error)
+	GetEquipmentEvents(ctx context.Context, equipmentID uuid.UUID, viewerID uuid.UUID, since time.Time) ([]models.CalendarEvent, error)
+}
@@ -0,0 +1,10 @@
+package calendar
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	CreateFeed() echo.HandlerFunc
+	GetFeeds() echo.HandlerFunc
+	RevokeFeed() echo.HandlerFunc
+	GetFeedCalendar() echo.HandlerFunc
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"equiptrack/config"
+	"equiptrack/internal/calendar"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type calendarHandlers struct {
+	cfg        *config.Config
+	calendarUC calendar.UseCase
+	logger     *logrus.Logger
+}
+
+// NewCalendarHandlers Calendar handlers constructor
+func NewCalendarHandlers(cfg *config.Config, calendarUC calendar.UseCase, log *logrus.Logger) calendar.Handlers {
+	return &calendarHandlers{cfg: cfg, calendarUC: calendarUC, logger: log}
+}
+
+func (h *calendarHandlers) CreateFeed() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		feed := &models.CalendarFeed{}
+		if err := utils.ReadRequest(c, feed); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		created, err := h.calendarUC.CreateFeed(c.Request().Context(), feed)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *calendarHandlers) GetFeeds() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		feeds, err := h.calendarUC.GetFeeds(c.Request().Context())
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, feeds)
+	}
+}
+
+func (h *calendarHandlers) RevokeFeed() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		fID, err := uuid.Parse(c.Param("feed_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.calendarUC.RevokeFeed(c.Request().Context(), fID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *calendarHandlers) GetFeedCalendar() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+		data, err := h.calendarUC.GetFeedCalendar(c.Request().Context(), token)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+		return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", data)
+	}
+}
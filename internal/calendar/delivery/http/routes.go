@@ -0,0 +1,20 @@
+package http
+
+import (
+	"equiptrack/internal/calendar"
+	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MapCalendarRoutes(calendarGroup *echo.Group, h calendar.Handlers, mw *middleware.MiddlewareManager) {
+	read := mw.RequireScope(models.ScopeEquipmentRead)
+	write := mw.RequireScope(models.ScopeReservationsWrite)
+
+	// feed token in the path authenticates the request
+	calendarGroup.GET("/:token", h.GetFeedCalendar())
+	calendarGroup.POST("/feeds", h.CreateFeed(), mw.AuthJWTMiddleware, write)
+	calendarGroup.GET("/feeds", h.GetFeeds(), mw.AuthJWTMiddleware, read)
+	calendarGroup.DELETE("/feeds/:feed_id", h.RevokeFeed(), mw.AuthJWTMiddleware, write)
+}
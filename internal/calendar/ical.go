@@ -0,0 +1,67 @@
+package calendar
+
+import (
+	"bytes"
+	"equiptrack/internal/models"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	icalTimeFormat = "20060102T150405Z"
+	// RFC 5545 limits content lines to 75 octets
+	icalLineLength = 75
+)
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Render events as an iCalendar (RFC 5545) document
+func Render(name string, events []models.CalendarEvent, now time.Time) []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//equiptrack//reservations//EN")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	writeLine(&buf, "X-WR-CALNAME:"+icalEscaper.Replace(name))
+
+	stamp := now.UTC().Format(icalTimeFormat)
+	for _, e := range events {
+		status := "CONFIRMED"
+		if e.Status == models.ReservationPending {
+			status = "TENTATIVE"
+		}
+
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, fmt.Sprintf("UID:reservation-%d@equiptrack", e.ReservationID))
+		writeLine(&buf, "DTSTAMP:"+stamp)
+		writeLine(&buf, "DTSTART:"+e.ReservationStart.UTC().Format(icalTimeFormat))
+		writeLine(&buf, "DTEND:"+e.ReservationEnd.UTC().Format(icalTimeFormat))
+		writeLine(&buf, "SUMMARY:"+icalEscaper.Replace(e.EquipmentName))
+		writeLine(&buf, "DESCRIPTION:"+icalEscaper.Replace(fmt.Sprintf("Reserved by %s (%s)", e.UserLogin, e.Status)))
+		writeLine(&buf, "STATUS:"+status)
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// Write CRLF terminated line folded into continuation lines starting with a space
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := icalLineLength
+	for len(line) > limit {
+		cut := limit
+		// don't split multibyte UTF-8 sequences
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icalLineLength - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
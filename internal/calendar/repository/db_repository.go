@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/internal/calendar"
+	"equiptrack/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type calendarRepo struct {
+	db *sql.DB
+}
+
+// Calendar Repository constructor
+func NewCalendarRepository(db *sql.DB) calendar.Repository {
+	return &calendarRepo{db: db}
+}
+
+func (r *calendarRepo) CreateFeed(ctx context.Context, feed *models.CalendarFeed) (*models.CalendarFeed, error) {
+	f := *feed
+	if err := r.db.QueryRowContext(
+		ctx, qCreateFeed, feed.UserID, feed.EquipmentID, feed.Name, feed.TokenHash,
+	).Scan(&f.FeedID, &f.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "calendarRepo.CreateFeed.QueryRowContext")
+	}
+	return &f, nil
+}
+
+func (r *calendarRepo) GetFeeds(ctx context.Context, userID uuid.UUID) ([]models.CalendarFeed, error) {
+	rows, err := r.db.QueryContext(ctx, qGetFeeds, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "calendarRepo.GetFeeds.QueryContext")
+	}
+	defer rows.Close()
+
+	var feeds = make([]models.CalendarFeed, 0)
+	for rows.Next() {
+		var f models.CalendarFeed
+		if err := rows.Scan(&f.FeedID, &f.UserID, &f.EquipmentID, &f.Name, &f.CreatedAt, &f.RevokedAt); err != nil {
+			return nil, errors.Wrap(err, "calendarRepo.GetFeeds.QueryContext.ScanRows")
+		}
+		feeds = append(feeds, f)
+	}
+	return feeds, nil
+}
+
+func (r *calendarRepo) GetFeedByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeed, error) {
+	f := &models.CalendarFeed{}
+	if err := r.db.QueryRowContext(ctx, qGetFeedByTokenHash, tokenHash).Scan(
+		&f.FeedID,
+		&f.UserID,
+		&f.EquipmentID,
+		&f.Name,
+		&f.TokenHash,
+		&f.CreatedAt,
+		&f.RevokedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "calendarRepo.GetFeedByTokenHash.QueryRowContext")
+	}
+	return f, nil
+}
+
+func (r *calendarRepo) RevokeFeed(ctx context.Context, userID uuid.UUID, feedID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qRevokeFeed, userID, feedID)
+	if err != nil {
+		return errors.Wrap(err, "calendarRepo.RevokeFeed.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "calendarRepo.RevokeFeed.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "calendarRepo.RevokeFeed.rowsAffected")
+	}
+	return nil
+}
+
+func (r *calendarRepo) GetUserEvents(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.CalendarEvent, error) {
+	rows, err := r.db.QueryContext(ctx, qGetUserEvents, userID, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "calendarRepo.GetUserEvents.QueryContext")
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "calendarRepo.GetUserEvents.ScanRows")
+	}
+	return events, nil
+}
+
+func (r *calendarRepo) GetEquipmentEvents(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	viewerID uuid.UUID,
+	since time.Time,
+) ([]models.CalendarEvent, error) {
+	rows, err := r.db.QueryContext(ctx, qGetEquipmentEvents, equipmentID, viewerID, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "calendarRepo.GetEquipmentEvents.QueryContext")
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "calendarRepo.GetEquipmentEvents.ScanRows")
+	}
+	return events, nil
+}
+
+func scanEvents(rows *sql.Rows) ([]models.CalendarEvent, error) {
+	var events = make([]models.CalendarEvent, 0)
+	for rows.Next() {
+		var e models.CalendarEvent
+		if err := rows.Scan(
+			&e.ReservationID,
+			&e.EquipmentID,
+			&e.EquipmentName,
+			&e.UserLogin,
+			&e.ReservationStart,
+			&e.ReservationEnd,
+			&e.Status,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,38 @@
+package repository
+
+const (
+	qCreateFeed = `INSERT INTO calendar_feeds (user_id, equipment_id, name, token_hash)
+	VALUES ($1, $2, $3, $4)
+	RETURNING feed_id, created_at`
+	qGetFeeds = `SELECT feed_id, user_id, equipment_id, name, created_at, revoked_at
+	FROM calendar_feeds
+	WHERE user_id = $1
+	ORDER BY created_at`
+	qGetFeedByTokenHash = `SELECT feed_id, user_id, equipment_id, name, token_hash, created_at, revoked_at
+	FROM calendar_feeds
+	WHERE token_hash = $1`
+	qRevokeFeed = `UPDATE calendar_feeds SET revoked_at = CURRENT_TIMESTAMP
+	WHERE user_id = $1 AND feed_id = $2 AND revoked_at IS NULL`
+
+	// overdue reservations are listed until the equipment is returned
+	qGetUserEvents = `SELECT ue.id, ue.equipment_id, e.name, u.login, ue.reservation_start, ue.reservation_end, ue.status
+	FROM usersEquipment ue
+	INNER JOIN equipment e using(equipment_id)
+	INNER JOIN users u using(user_id)
+	WHERE ue.user_id = $1
+	AND (ue.status IN ('pending', 'confirmed') AND ue.reservation_end > $2 OR ue.status = 'overdue')
+	ORDER BY ue.reservation_start`
+	// team scoped equipment stays visible only while the feed owner is a member or an admin
+	qGetEquipmentEvents = `SELECT ue.id, ue.equipment_id, e.name, u.login, ue.reservation_start, ue.reservation_end, ue.status
+	FROM usersEquipment ue
+	INNER JOIN equipment e using(equipment_id)
+	INNER JOIN users u using(user_id)
+	WHERE ue.equipment_id = $1
+	AND (ue.status IN ('pending', 'confirmed') AND ue.reservation_end > $3 OR ue.status = 'overdue')
+	AND (
+		e.team_id IS NULL
+		OR EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = e.team_id AND tm.user_id = $2)
+		OR EXISTS (SELECT 1 FROM users a WHERE a.user_id = $2 AND a.role = 'admin')
+	)
+	ORDER BY ue.reservation_start`
+)
@@ -0,0 +1,15 @@
+package calendar
+
+import (
+	"context"
+	"equiptrack/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type UseCase interface {
+	CreateFeed(ctx context.Context, feed *models.CalendarFeed) (*models.CalendarFeedWithToken, error)
+	GetFeeds(ctx context.Context) ([]models.CalendarFeed, error)
+	RevokeFeed(ctx context.Context, feedID uuid.UUID) error
+	GetFeedCalendar(ctx context.Context, token string) ([]byte, error)
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/config"
+	"equiptrack/internal/audit"
+	"equiptrack/internal/calendar"
+	"equiptrack/internal/equipment"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Feeds also list reservations which ended recently
+const feedHistory = 30 * 24 * time.Hour
+
+type calendarUC struct {
+	cfg          *config.Config
+	calendarRepo calendar.Repository
+	equipmentUC  equipment.UseCase
+	auditor      audit.Recorder
+	logger       *logrus.Logger
+}
+
+func NewCalendarUseCase(
+	cfg *config.Config,
+	calendarRepo calendar.Repository,
+	equipmentUC equipment.UseCase,
+	auditor audit.Recorder,
+	log *logrus.Logger,
+) calendar.UseCase {
+	return &calendarUC{cfg: cfg, calendarRepo: calendarRepo, equipmentUC: equipmentUC, auditor: auditor, logger: log}
+}
+
+func (u *calendarUC) CreateFeed(ctx context.Context, feed *models.CalendarFeed) (*models.CalendarFeedWithToken, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if feed.EquipmentID != nil {
+		e, err := u.equipmentUC.GetByID(ctx, *feed.EquipmentID)
+		if err != nil {
+			return nil, err
+		}
+		if feed.Name == "" {
+			feed.Name = e.Name
+		}
+	}
+	if feed.Name == "" {
+		feed.Name = "My reservations"
+	}
+
+	token, err := utils.NewSecureToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "calendarUC.CreateFeed.NewSecureToken")
+	}
+	feed.UserID = user.UserID
+	feed.TokenHash = utils.HashToken(token)
+
+	created, err := u.calendarRepo.CreateFeed(ctx, feed)
+	if err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityCalendarFeed, created.FeedID.String(), nil, created)
+	return &models.CalendarFeedWithToken{
+		CalendarFeed: created,
+		Token:        token,
+		URL:          fmt.Sprintf("%s/api/calendar/%s.ics", u.cfg.Server.PublicURL, token),
+	}, nil
+}
+
+func (u *calendarUC) GetFeeds(ctx context.Context) ([]models.CalendarFeed, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.calendarRepo.GetFeeds(ctx, user.UserID)
+}
+
+func (u *calendarUC) RevokeFeed(ctx context.Context, feedID uuid.UUID) error {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	if err = u.calendarRepo.RevokeFeed(ctx, user.UserID, feedID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditRevoke, models.AuditEntityCalendarFeed, feedID.String(), nil, nil)
+	return nil
+}
+
+// Feed is authenticated by its token alone, calendar clients can't send the JWT
+func (u *calendarUC) GetFeedCalendar(ctx context.Context, token string) ([]byte, error) {
+	feed, err := u.calendarRepo.GetFeedByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, httpErrors.InvalidFeedToken
+		}
+		return nil, err
+	}
+	if feed.RevokedAt != nil {
+		return nil, httpErrors.InvalidFeedToken
+	}
+
+	now := time.Now()
+	var events []models.CalendarEvent
+	if feed.EquipmentID != nil {
+		events, err = u.calendarRepo.GetEquipmentEvents(ctx, *feed.EquipmentID, feed.UserID, now.Add(-feedHistory))
+	} else {
+		events, err = u.calendarRepo.GetUserEvents(ctx, feed.UserID, now.Add(-feedHistory))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return calendar.Render(feed.Name, events, now), nil
+}
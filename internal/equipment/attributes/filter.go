@@ -0,0 +1,59 @@
+package attributes
+
+import (
+	"equiptrack/internal/models"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Query parameters starting with the prefix filter equipment listings by attribute
+	FilterPrefix = "attr."
+
+	// Upper bound of attribute filters in one listing
+	MaxFilters = 10
+)
+
+var number = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// Two character operators come first so >= isn't read as >
+var operators = []string{
+	models.AttributeGreaterOrEqual,
+	models.AttributeLessOrEqual,
+	models.AttributeNotEqual,
+	models.AttributeEqual,
+	models.AttributeGreater,
+	models.AttributeLess,
+}
+
+// ParseFilter parses expression like attr.ram_gb>=16. Equality compares the attribute as
+// text, ordering operators compare numeric attributes and need a numeric value
+func ParseFilter(expr string) (*models.AttributeFilter, error) {
+	rest, ok := strings.CutPrefix(expr, FilterPrefix)
+	if !ok {
+		return nil, fmt.Errorf("attribute filter must start with %s", FilterPrefix)
+	}
+	i := strings.IndexAny(rest, "=!<>")
+	if i < 0 {
+		return nil, fmt.Errorf("attribute filter %q has no operator", expr)
+	}
+	name := rest[:i]
+	if !propertyName.MatchString(name) {
+		return nil, fmt.Errorf("invalid attribute name %q", name)
+	}
+
+	for _, op := range operators {
+		value, ok := strings.CutPrefix(rest[i:], op)
+		if !ok {
+			continue
+		}
+		if op != models.AttributeEqual && op != models.AttributeNotEqual {
+			if !number.MatchString(value) {
+				return nil, fmt.Errorf("attribute filter %q compares with %s and needs a number", expr, op)
+			}
+		}
+		return &models.AttributeFilter{Name: name, Op: op, Value: value}, nil
+	}
+	return nil, fmt.Errorf("attribute filter %q has no operator", expr)
+}
@@ -0,0 +1,49 @@
+package attributes
+
+import (
+	"equiptrack/internal/models"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    *models.AttributeFilter
+		wantErr bool
+	}{
+		{expr: "attr.os=linux", want: &models.AttributeFilter{Name: "os", Op: models.AttributeEqual, Value: "linux"}},
+		{expr: "attr.os!=linux", want: &models.AttributeFilter{Name: "os", Op: models.AttributeNotEqual, Value: "linux"}},
+		{expr: "attr.ram_gb>=16", want: &models.AttributeFilter{Name: "ram_gb", Op: models.AttributeGreaterOrEqual, Value: "16"}},
+		{expr: "attr.ram_gb<=16", want: &models.AttributeFilter{Name: "ram_gb", Op: models.AttributeLessOrEqual, Value: "16"}},
+		{expr: "attr.ram_gb>16", want: &models.AttributeFilter{Name: "ram_gb", Op: models.AttributeGreater, Value: "16"}},
+		{expr: "attr.weight<-0.5", want: &models.AttributeFilter{Name: "weight", Op: models.AttributeLess, Value: "-0.5"}},
+		{expr: "attr.note=a=b", want: &models.AttributeFilter{Name: "note", Op: models.AttributeEqual, Value: "a=b"}},
+		{expr: "attr.note=", want: &models.AttributeFilter{Name: "note", Op: models.AttributeEqual, Value: ""}},
+		{expr: "os=linux", wantErr: true},
+		{expr: "attr.os", wantErr: true},
+		{expr: "attr.=linux", wantErr: true},
+		{expr: "attr.o s=linux", wantErr: true},
+		{expr: "attr.ram_gb>=many", wantErr: true},
+		{expr: "attr.ram_gb>1e3", wantErr: true},
+		{expr: "attr.ram_gb<", wantErr: true},
+		{expr: "attr.ram_gb!16", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseFilter(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseFilter() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("ParseFilter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
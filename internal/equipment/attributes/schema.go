@@ -0,0 +1,245 @@
+package attributes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+)
+
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+
+	// Upper bound of properties in one schema
+	MaxProperties = 100
+)
+
+var propertyName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Subset of JSON Schema describing the custom attributes of an equipment type: an object
+// whose properties are strings, integers, numbers or booleans. Keywords outside the subset
+// are rejected rather than silently ignored
+type Schema struct {
+	SchemaURI   string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	// Properties not listed are allowed unless AdditionalProperties is false
+	Properties           map[string]*Property `json:"properties"`
+	Required             []string             `json:"required,omitempty"`
+	AdditionalProperties *bool                `json:"additionalProperties,omitempty"`
+}
+
+type Property struct {
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Type        string        `json:"type"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Minimum     *float64      `json:"minimum,omitempty"`
+	Maximum     *float64      `json:"maximum,omitempty"`
+	MinLength   *int          `json:"minLength,omitempty"`
+	MaxLength   *int          `json:"maxLength,omitempty"`
+	Pattern     string        `json:"pattern,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Parse schema like {"type":"object","properties":{"ram_gb":{"type":"integer","minimum":1}},"required":["ram_gb"]}
+func Parse(raw []byte) (*Schema, error) {
+	s := &Schema{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	decoder.UseNumber()
+	if err := decoder.Decode(s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %v", err)
+	}
+
+	if s.Type != "" && s.Type != "object" {
+		return nil, fmt.Errorf("schema type must be object")
+	}
+	if len(s.Properties) > MaxProperties {
+		return nil, fmt.Errorf("at most %d properties allowed", MaxProperties)
+	}
+	for name, p := range s.Properties {
+		if !propertyName.MatchString(name) {
+			return nil, fmt.Errorf("invalid property name %q, only letters, digits, _ and - are allowed", name)
+		}
+		if p == nil {
+			return nil, fmt.Errorf("property %q has no definition", name)
+		}
+		if err := p.check(); err != nil {
+			return nil, fmt.Errorf("property %q: %v", name, err)
+		}
+	}
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok {
+			return nil, fmt.Errorf("required property %q is not defined", name)
+		}
+	}
+	return s, nil
+}
+
+func (p *Property) check() error {
+	switch p.Type {
+	case TypeString, TypeInteger, TypeNumber, TypeBoolean:
+	default:
+		return fmt.Errorf("unsupported type %q", p.Type)
+	}
+
+	numeric := p.Type == TypeInteger || p.Type == TypeNumber
+	if (p.Minimum != nil || p.Maximum != nil) && !numeric {
+		return fmt.Errorf("minimum and maximum apply to numbers only")
+	}
+	if p.Minimum != nil && p.Maximum != nil && *p.Minimum > *p.Maximum {
+		return fmt.Errorf("minimum is greater than maximum")
+	}
+	if (p.MinLength != nil || p.MaxLength != nil || p.Pattern != "") && p.Type != TypeString {
+		return fmt.Errorf("minLength, maxLength and pattern apply to strings only")
+	}
+	if p.MinLength != nil && p.MaxLength != nil && *p.MinLength > *p.MaxLength {
+		return fmt.Errorf("minLength is greater than maxLength")
+	}
+	if p.Pattern != "" {
+		var err error
+		if p.pattern, err = regexp.Compile(p.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	}
+	for _, value := range p.Enum {
+		if err := p.checkType(value); err != nil {
+			return fmt.Errorf("enum value %v: %v", value, err)
+		}
+	}
+	return nil
+}
+
+// Validate attributes, a JSON object, against the schema. Empty attributes are an empty object
+func (s *Schema) Validate(raw []byte) error {
+	attrs, err := Decode(raw)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range s.Required {
+		if value, ok := attrs[name]; !ok || value == nil {
+			return fmt.Errorf("attribute %q is required", name)
+		}
+	}
+
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	// reports the same attribute first on every call
+	sort.Strings(names)
+	for _, name := range names {
+		p, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return fmt.Errorf("attribute %q is not defined for the equipment type", name)
+			}
+			continue
+		}
+		if err := p.validate(attrs[name]); err != nil {
+			return fmt.Errorf("attribute %q %v", name, err)
+		}
+	}
+	return nil
+}
+
+// Decode attributes, which must be a JSON object. Numbers are kept as json.Number
+func Decode(raw []byte) (map[string]interface{}, error) {
+	attrs := make(map[string]interface{})
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return attrs, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&attrs); err != nil || attrs == nil {
+		return nil, fmt.Errorf("attributes must be a JSON object")
+	}
+	return attrs, nil
+}
+
+func (p *Property) validate(value interface{}) error {
+	if err := p.checkType(value); err != nil {
+		return err
+	}
+
+	if len(p.Enum) > 0 {
+		found := false
+		for _, allowed := range p.Enum {
+			if equal(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("must be one of %v", p.Enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		if p.Minimum != nil && f < *p.Minimum {
+			return fmt.Errorf("must be at least %v", *p.Minimum)
+		}
+		if p.Maximum != nil && f > *p.Maximum {
+			return fmt.Errorf("must be at most %v", *p.Maximum)
+		}
+	case string:
+		length := len([]rune(v))
+		if p.MinLength != nil && length < *p.MinLength {
+			return fmt.Errorf("must be at least %d characters long", *p.MinLength)
+		}
+		if p.MaxLength != nil && length > *p.MaxLength {
+			return fmt.Errorf("must be at most %d characters long", *p.MaxLength)
+		}
+		if p.pattern != nil && !p.pattern.MatchString(v) {
+			return fmt.Errorf("must match %s", p.Pattern)
+		}
+	}
+	return nil
+}
+
+func (p *Property) checkType(value interface{}) error {
+	switch p.Type {
+	case TypeString:
+		if _, ok := value.(string); ok {
+			return nil
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+	case TypeNumber:
+		if _, ok := value.(json.Number); ok {
+			return nil
+		}
+	case TypeInteger:
+		// 16.0 is an integer too, as in JSON Schema
+		if n, ok := value.(json.Number); ok {
+			if f, err := n.Float64(); err == nil && f == math.Trunc(f) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("must be of type %s", p.Type)
+}
+
+func equal(a interface{}, b interface{}) bool {
+	an, aNumber := a.(json.Number)
+	bn, bNumber := b.(json.Number)
+	if aNumber && bNumber {
+		af, _ := an.Float64()
+		bf, _ := bn.Float64()
+		return af == bf
+	}
+	return a == b
+}
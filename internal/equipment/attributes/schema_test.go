@@ -0,0 +1,114 @@
+package attributes
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{name: "every type", schema: `{"type":"object","properties":{
+			"model":{"type":"string","minLength":1,"maxLength":20,"pattern":"^[A-Z]"},
+			"ram_gb":{"type":"integer","minimum":1,"maximum":512},
+			"weight_kg":{"type":"number"},
+			"has_gpu":{"type":"boolean"},
+			"os":{"type":"string","enum":["linux","windows"]}
+		},"required":["ram_gb"],"additionalProperties":false}`},
+		{name: "type may be left out", schema: `{"properties":{}}`},
+		{name: "not a JSON object", schema: `[]`, wantErr: true},
+		{name: "non object type", schema: `{"type":"array","properties":{}}`, wantErr: true},
+		{name: "unknown keyword", schema: `{"properties":{},"oneOf":[]}`, wantErr: true},
+		{name: "unknown property keyword", schema: `{"properties":{"a":{"type":"string","format":"email"}}}`, wantErr: true},
+		{name: "unsupported property type", schema: `{"properties":{"a":{"type":"array"}}}`, wantErr: true},
+		{name: "property without definition", schema: `{"properties":{"a":null}}`, wantErr: true},
+		{name: "invalid property name", schema: `{"properties":{"a b":{"type":"string"}}}`, wantErr: true},
+		{name: "required property not defined", schema: `{"properties":{},"required":["a"]}`, wantErr: true},
+		{name: "minimum on a string", schema: `{"properties":{"a":{"type":"string","minimum":1}}}`, wantErr: true},
+		{name: "minimum above maximum", schema: `{"properties":{"a":{"type":"number","minimum":2,"maximum":1}}}`, wantErr: true},
+		{name: "pattern on a number", schema: `{"properties":{"a":{"type":"number","pattern":"1"}}}`, wantErr: true},
+		{name: "minLength above maxLength", schema: `{"properties":{"a":{"type":"string","minLength":3,"maxLength":2}}}`,
+			wantErr: true},
+		{name: "invalid pattern", schema: `{"properties":{"a":{"type":"string","pattern":"("}}}`, wantErr: true},
+		{name: "enum value of another type", schema: `{"properties":{"a":{"type":"integer","enum":[1,"2"]}}}`, wantErr: true},
+		{name: "fractional enum value of an integer", schema: `{"properties":{"a":{"type":"integer","enum":[1.5]}}}`,
+			wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.schema))
+			if tt.wantErr && err == nil {
+				t.Fatal("Parse() want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Parse([]byte(`{"type":"object","properties":{
+		"model":{"type":"string","minLength":2,"maxLength":5,"pattern":"^[A-Z]"},
+		"ram_gb":{"type":"integer","minimum":1,"maximum":512},
+		"weight_kg":{"type":"number","minimum":0.5},
+		"has_gpu":{"type":"boolean"},
+		"os":{"type":"string","enum":["linux","windows"]},
+		"cores":{"type":"integer","enum":[4,8]}
+	},"required":["ram_gb"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed, err := Parse([]byte(`{"properties":{"a":{"type":"string"}},"additionalProperties":false}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		schema  *Schema
+		attrs   string
+		wantErr bool
+	}{
+		{name: "all valid", schema: schema,
+			attrs: `{"model":"Xp","ram_gb":16,"weight_kg":1.2,"has_gpu":true,"os":"linux","cores":8}`},
+		{name: "integer written with a fraction of zero", schema: schema, attrs: `{"ram_gb":16.0}`},
+		{name: "integer given for a number", schema: schema, attrs: `{"ram_gb":1,"weight_kg":2}`},
+		{name: "enum compares numbers by value", schema: schema, attrs: `{"ram_gb":1,"cores":8.0}`},
+		{name: "undefined attributes are allowed", schema: schema, attrs: `{"ram_gb":1,"color":"red"}`},
+		{name: "required missing", schema: schema, attrs: `{}`, wantErr: true},
+		{name: "required null", schema: schema, attrs: `{"ram_gb":null}`, wantErr: true},
+		{name: "empty attributes miss required", schema: schema, attrs: ``, wantErr: true},
+		{name: "fractional integer", schema: schema, attrs: `{"ram_gb":16.5}`, wantErr: true},
+		{name: "integer as a string", schema: schema, attrs: `{"ram_gb":"16"}`, wantErr: true},
+		{name: "below minimum", schema: schema, attrs: `{"ram_gb":0}`, wantErr: true},
+		{name: "above maximum", schema: schema, attrs: `{"ram_gb":1024}`, wantErr: true},
+		{name: "number below minimum", schema: schema, attrs: `{"ram_gb":1,"weight_kg":0.4}`, wantErr: true},
+		{name: "boolean as a string", schema: schema, attrs: `{"ram_gb":1,"has_gpu":"true"}`, wantErr: true},
+		{name: "not in enum", schema: schema, attrs: `{"ram_gb":1,"os":"macos"}`, wantErr: true},
+		{name: "number not in enum", schema: schema, attrs: `{"ram_gb":1,"cores":6}`, wantErr: true},
+		{name: "string too short", schema: schema, attrs: `{"ram_gb":1,"model":"X"}`, wantErr: true},
+		{name: "string too long", schema: schema, attrs: `{"ram_gb":1,"model":"Xyzzyx"}`, wantErr: true},
+		{name: "length counts characters", schema: schema, attrs: `{"ram_gb":1,"model":"Xäääá"}`},
+		{name: "pattern mismatch", schema: schema, attrs: `{"ram_gb":1,"model":"xp"}`, wantErr: true},
+		{name: "not an object", schema: schema, attrs: `[1]`, wantErr: true},
+		{name: "closed schema", schema: closed, attrs: `{"a":"x"}`},
+		{name: "closed schema rejects undefined", schema: closed, attrs: `{"a":"x","b":"y"}`, wantErr: true},
+		{name: "closed schema with empty attributes", schema: closed, attrs: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate([]byte(tt.attrs))
+			if tt.wantErr && err == nil {
+				t.Fatal("Validate() want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+		})
+	}
+}
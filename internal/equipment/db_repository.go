@@ -2,19 +2,85 @@ package equipment
 
 import (
 	"context"
+	"encoding/json"
 	"equiptrack/internal/models"
 	"equiptrack/internal/utils"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Repository interface {
-	Create(ctx context.Context, equipment *models.Equipment) (*models.Equipment, error)
+	Create(ctx context.Context, equipment *models.Equipment, tagPrefix string) (*models.Equipment, error)
 	Update(ctx context.Context, equipment *models.Equipment) error
-	Delete(ctx context.Context, equipmentID uuid.UUID) error
+	Archive(ctx context.Context, equipmentID uuid.UUID, force bool) ([]models.UsersEquipment, bool, error)
+	Restore(ctx context.Context, equipmentID uuid.UUID) error
 	GetByID(ctx context.Context, equipmentID uuid.UUID) (*models.Equipment, error)
-	GetEquipments(ctx context.Context, pq *utils.PaginationQuery) (*models.EquipmentList, error)
+	GetIDByCode(ctx context.Context, code string) (uuid.UUID, error)
+	GetEquipments(ctx context.Context, pq *utils.PaginationQuery, filter *models.EquipmentFilter) (*models.EquipmentList, error)
 	GetUserEquipments(ctx context.Context, pq *utils.PaginationQuery, id uuid.UUID) (*models.EquipmentList, error)
 	GetReservationInfo(ctx context.Context, equipmentId uuid.UUID) (*models.ReservationInfoResponse, error)
 	ReserveEquipment(ctx context.Context, reservation *models.UsersEquipment) (bool, error)
+	IsEquipmentReservedAt(ctx context.Context, equipmentId uuid.UUID, start time.Time, end time.Time, quantity int) (bool, error)
+	GetBookingUsage(ctx context.Context, userID uuid.UUID, monthStart time.Time, equipmentType string) (*models.BookingUsage, error)
+	GetReservation(ctx context.Context, reservationID int) (*models.UsersEquipment, error)
+	GetPendingReservations(ctx context.Context, approver *models.User) ([]models.UsersEquipment, error)
+	UpdateReservationStatus(ctx context.Context, reservationID int, from string, to string, reason string) error
+	ShortenReservation(ctx context.Context, reservationID int, end time.Time) error
+	JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) error
+	GetWaitlistEntry(ctx context.Context, entryID int) (*models.WaitlistEntry, error)
+	GetUserWaitlist(ctx context.Context, userID uuid.UUID) ([]models.WaitlistEntry, error)
+	GetEquipmentWaitlist(ctx context.Context, equipmentID uuid.UUID) ([]models.WaitlistEntry, error)
+	GetWaitingEntries(ctx context.Context, equipmentID uuid.UUID, start time.Time, end time.Time) ([]models.WaitingEntry, error)
+	UpdateWaitlistStatus(ctx context.Context, entryID int, from string, to string, reservationID *int) error
+	RescheduleReservations(ctx context.Context, equipmentID uuid.UUID, reservations []models.UsersEquipment) ([]models.UsersEquipment, error)
+	CreateSeries(ctx context.Context, series *models.ReservationSeries, occurrences []models.UsersEquipment) ([]models.UsersEquipment, error)
+	GetSeries(ctx context.Context, seriesID uuid.UUID) (*models.ReservationSeries, error)
+	CancelSeries(ctx context.Context, seriesID uuid.UUID) ([]models.UsersEquipment, error)
+	CreateHandover(ctx context.Context, handover *models.Handover) (bool, error)
+	GetCheckedOutReservation(ctx context.Context, equipmentID uuid.UUID) (int, error)
+	GetReservationHandovers(ctx context.Context, reservationID int) ([]models.Handover, error)
+	GetEquipmentHandovers(ctx context.Context, equipmentID uuid.UUID, pq *utils.PaginationQuery) ([]models.Handover, error)
+
+	LocationExists(ctx context.Context, locationID uuid.UUID) (bool, error)
+	MoveEquipment(ctx context.Context, change *models.LocationChange) (bool, error)
+	GetLocationHistory(ctx context.Context, equipmentID uuid.UUID, pq *utils.PaginationQuery) ([]models.LocationChange, error)
+
+	SetStatus(ctx context.Context, equipmentID uuid.UUID, status string) error
+	ScheduleMaintenance(ctx context.Context, maintenance *models.Maintenance) ([]models.UsersEquipment, error)
+	GetMaintenance(ctx context.Context, equipmentID uuid.UUID, maintenanceID int) (*models.Maintenance, error)
+	GetEquipmentMaintenance(ctx context.Context, equipmentID uuid.UUID) ([]models.Maintenance, error)
+	CancelMaintenance(ctx context.Context, equipmentID uuid.UUID, maintenanceID int) error
+
+	CreateUnit(ctx context.Context, unit *models.EquipmentUnit, tagPrefix string) (bool, error)
+	UpdateUnit(ctx context.Context, unit *models.EquipmentUnit) error
+	DeleteUnit(ctx context.Context, equipmentID uuid.UUID, unitID uuid.UUID) error
+	GetUnit(ctx context.Context, equipmentID uuid.UUID, unitID uuid.UUID) (*models.EquipmentUnit, error)
+	GetUnits(ctx context.Context, equipmentID uuid.UUID) ([]models.EquipmentUnit, error)
+	CountUnits(ctx context.Context, equipmentID uuid.UUID) (int, error)
+	GetBookedPeak(ctx context.Context, equipmentID uuid.UUID) (int, error)
+
+	CreateKit(ctx context.Context, kit *models.Kit) error
+	UpdateKit(ctx context.Context, kit *models.Kit) error
+	DeleteKit(ctx context.Context, kitID uuid.UUID) error
+	GetKit(ctx context.Context, kitID uuid.UUID) (*models.Kit, error)
+	GetKits(ctx context.Context) ([]models.Kit, error)
+	ReserveKit(ctx context.Context, kr *models.KitReservation, reservations []models.UsersEquipment) ([]models.UsersEquipment, error)
+	GetKitReservation(ctx context.Context, kitReservationID uuid.UUID) (*models.KitReservation, error)
+	CancelKitReservation(ctx context.Context, kitReservationID uuid.UUID) ([]models.UsersEquipment, error)
+
+	SetEquipmentType(ctx context.Context, equipmentType *models.EquipmentType) error
+	DeleteEquipmentType(ctx context.Context, equipmentType string) error
+	GetEquipmentType(ctx context.Context, equipmentType string) (*models.EquipmentType, error)
+	GetEquipmentTypes(ctx context.Context) ([]models.EquipmentType, error)
+	GetTypeAttributes(ctx context.Context, equipmentType string) (map[uuid.UUID]json.RawMessage, error)
+	MarkOverdue(ctx context.Context) ([]models.DueReservation, error)
+	ClaimStartReminders(ctx context.Context, before time.Time) ([]models.DueReservation, error)
+	ClaimEndReminders(ctx context.Context, before time.Time) ([]models.DueReservation, error)
+	IsTeamMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (bool, error)
+
+	CreateAttachment(ctx context.Context, attachment *models.Attachment) error
+	GetAttachment(ctx context.Context, equipmentID uuid.UUID, attachmentID uuid.UUID) (*models.Attachment, error)
+	GetAttachments(ctx context.Context, equipmentID uuid.UUID) ([]models.Attachment, error)
+	DeleteAttachment(ctx context.Context, equipmentID uuid.UUID, attachmentID uuid.UUID) error
 }
@@ -6,8 +6,55 @@ type Handlers interface {
 	Create() echo.HandlerFunc
 	Update() echo.HandlerFunc
 	Delete() echo.HandlerFunc
+	Restore() echo.HandlerFunc
 	GetByID() echo.HandlerFunc
+	Lookup() echo.HandlerFunc
+	GetQRCode() echo.HandlerFunc
+	UploadAttachment() echo.HandlerFunc
+	GetAttachment() echo.HandlerFunc
+	DeleteAttachment() echo.HandlerFunc
+	GetLabelSheet() echo.HandlerFunc
+	GetLabelTemplates() echo.HandlerFunc
 	GetEquipments() echo.HandlerFunc
+	GetArchivedEquipments() echo.HandlerFunc
 	GetReservationInfo() echo.HandlerFunc
 	ReserveEquipment() echo.HandlerFunc
+	GetPendingReservations() echo.HandlerFunc
+	ApproveReservation() echo.HandlerFunc
+	RejectReservation() echo.HandlerFunc
+	CancelReservation() echo.HandlerFunc
+	ShortenReservation() echo.HandlerFunc
+	RescheduleReservation() echo.HandlerFunc
+	CreateSeries() echo.HandlerFunc
+	GetSeries() echo.HandlerFunc
+	UpdateSeries() echo.HandlerFunc
+	CancelSeries() echo.HandlerFunc
+	CheckOut() echo.HandlerFunc
+	CheckIn() echo.HandlerFunc
+	GetReservationHandovers() echo.HandlerFunc
+	GetEquipmentHandovers() echo.HandlerFunc
+	GetLocationHistory() echo.HandlerFunc
+	SetStatus() echo.HandlerFunc
+	ScheduleMaintenance() echo.HandlerFunc
+	GetMaintenance() echo.HandlerFunc
+	CancelMaintenance() echo.HandlerFunc
+	CreateUnit() echo.HandlerFunc
+	UpdateUnit() echo.HandlerFunc
+	DeleteUnit() echo.HandlerFunc
+	GetUnits() echo.HandlerFunc
+	CreateKit() echo.HandlerFunc
+	UpdateKit() echo.HandlerFunc
+	DeleteKit() echo.HandlerFunc
+	GetKit() echo.HandlerFunc
+	GetKits() echo.HandlerFunc
+	ReserveKit() echo.HandlerFunc
+	GetKitReservation() echo.HandlerFunc
+	CancelKitReservation() echo.HandlerFunc
+	SetEquipmentType() echo.HandlerFunc
+	DeleteEquipmentType() echo.HandlerFunc
+	GetEquipmentType() echo.HandlerFunc
+	GetEquipmentTypes() echo.HandlerFunc
+	JoinWaitlist() echo.HandlerFunc
+	LeaveWaitlist() echo.HandlerFunc
+	GetWaitlist() echo.HandlerFunc
 }
@@ -1,12 +1,22 @@
 package http
 
 import (
+	"context"
 	"equiptrack/config"
 	"equiptrack/internal/equipment"
+	"equiptrack/internal/equipment/attributes"
+	"equiptrack/internal/equipment/label"
 	httpErrors "equiptrack/internal/httpErrors"
 	"equiptrack/internal/models"
 	"equiptrack/internal/utils"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -42,13 +52,13 @@ func (h *equipmentHandlers) Create() echo.HandlerFunc {
 
 func (h *equipmentHandlers) Update() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		equipment := &models.Equipment{}
-		if err := c.Bind(equipment); err != nil {
+		update := &models.EquipmentUpdate{}
+		if err := c.Bind(update); err != nil {
 			utils.LogResponseError(c, h.logger, err)
 			return c.JSON(httpErrors.ErrorResponse(err))
 		}
 
-		err := h.equipmentUC.Update(c.Request().Context(), equipment)
+		err := h.equipmentUC.Update(c.Request().Context(), update)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
 			return c.JSON(httpErrors.ErrorResponse(err))
@@ -64,7 +74,9 @@ func (h *equipmentHandlers) Delete() echo.HandlerFunc {
 			return utils.ErrResponseWithLog(c, h.logger, err)
 		}
 
-		if err = h.equipmentUC.Delete(c.Request().Context(), eID); err != nil {
+		force := c.QueryParam("force") == "true"
+
+		if err = h.equipmentUC.Delete(c.Request().Context(), eID, force); err != nil {
 			return utils.ErrResponseWithLog(c, h.logger, err)
 		}
 
@@ -72,6 +84,38 @@ func (h *equipmentHandlers) Delete() echo.HandlerFunc {
 	}
 }
 
+func (h *equipmentHandlers) Restore() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		restored, err := h.equipmentUC.Restore(c.Request().Context(), eID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, restored)
+	}
+}
+
+func (h *equipmentHandlers) GetArchivedEquipments() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		equipmentList, err := h.equipmentUC.GetArchivedEquipments(c.Request().Context(), paginationQuery)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, equipmentList)
+	}
+}
+
 func (h *equipmentHandlers) GetByID() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		eID, err := uuid.Parse(c.Param("equipment_id"))
@@ -89,6 +133,173 @@ func (h *equipmentHandlers) GetByID() echo.HandlerFunc {
 	}
 }
 
+// Scanned label or typed asset tag, serial number or ID
+func (h *equipmentHandlers) Lookup() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		equipment, err := h.equipmentUC.Lookup(c.Request().Context(), c.QueryParam("code"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, equipment)
+	}
+}
+
+// QR code label, format=png (default) or svg and size in pixels
+func (h *equipmentHandlers) GetQRCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		size := 0
+		if s := c.QueryParam("size"); s != "" {
+			if size, err = strconv.Atoi(s); err != nil {
+				return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+			}
+		}
+		format := c.QueryParam("format")
+
+		image, err := h.equipmentUC.GetQRCode(c.Request().Context(), eID, format, size)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if format == label.FormatSVG {
+			return c.Blob(http.StatusOK, "image/svg+xml", image)
+		}
+		return c.Blob(http.StatusOK, "image/png", image)
+	}
+}
+
+// PDF label sheet of the selected equipment
+func (h *equipmentHandlers) GetLabelSheet() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &models.LabelSheetRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		sheet, err := h.equipmentUC.GetLabelSheet(c.Request().Context(), request)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="labels.pdf"`)
+		return c.Blob(http.StatusOK, "application/pdf", sheet)
+	}
+}
+
+// Multipart upload of a photo or document, the file is expected in the "file" field
+func (h *equipmentHandlers) UploadAttachment() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// room for the multipart framing on top of the file itself
+		limit := (h.cfg.Storage.MaxUploadSize + 1) << 20
+		c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, limit)
+		header, err := c.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return utils.ErrResponseWithLog(c, h.logger,
+					httpErrors.NewRestErrorWithMessage(http.StatusRequestEntityTooLarge, httpErrors.ErrFileTooLarge, nil))
+			}
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.NewBadRequestError(err))
+		}
+		file, err := header.Open()
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		defer file.Close()
+
+		attachment, err := h.equipmentUC.UploadAttachment(c.Request().Context(), eID, header.Filename, file, header.Size)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		return c.JSON(http.StatusCreated, attachment)
+	}
+}
+
+// Streams the stored file, or its thumbnail with ?thumbnail=true
+func (h *equipmentHandlers) GetAttachment() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		aID, err := uuid.Parse(c.Param("attachment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		thumbnail := c.QueryParam("thumbnail") == "true"
+
+		attachment, content, err := h.equipmentUC.GetAttachmentContent(c.Request().Context(), eID, aID, thumbnail)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		defer content.Close()
+
+		// RFC 6266 parameter, names which can't be encoded are left out
+		disposition := mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName})
+		if disposition == "" {
+			disposition = "inline"
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, disposition)
+		c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+		return c.Stream(http.StatusOK, attachment.ContentType, content)
+	}
+}
+
+func (h *equipmentHandlers) DeleteAttachment() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		aID, err := uuid.Parse(c.Param("attachment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.equipmentUC.DeleteAttachment(c.Request().Context(), eID, aID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) GetLabelTemplates() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, h.equipmentUC.GetLabelTemplates())
+	}
+}
+
+// Attribute filters like attr.ram_gb>=16 are whole query parameters, the raw query is split
+// by hand since url parsing would cut them at the first =
+func attributeFilters(c echo.Context) ([]models.AttributeFilter, error) {
+	filters := make([]models.AttributeFilter, 0)
+	for _, part := range strings.Split(c.Request().URL.RawQuery, "&") {
+		expr, err := url.QueryUnescape(part)
+		if err != nil || !strings.HasPrefix(expr, attributes.FilterPrefix) {
+			continue
+		}
+		filter, err := attributes.ParseFilter(expr)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(err.Error())
+		}
+		filters = append(filters, *filter)
+	}
+	if len(filters) > attributes.MaxFilters {
+		return nil, httpErrors.NewBadRequestError(fmt.Sprintf("at most %d attribute filters allowed", attributes.MaxFilters))
+	}
+	return filters, nil
+}
+
 func (h *equipmentHandlers) GetEquipments() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		// time.Sleep(5 * time.Second)
@@ -100,8 +311,19 @@ func (h *equipmentHandlers) GetEquipments() echo.HandlerFunc {
 
 		id_str := c.QueryParam("user_id")
 		if id_str == "" {
-			// return all equipments
-			equipmentList, err := h.equipmentUC.GetEquipments(c.Request().Context(), paginationQuery)
+			// return all equipments, optionally within a location and with matching attributes
+			filter := &models.EquipmentFilter{}
+			if l := c.QueryParam("location_id"); l != "" {
+				parsed, err := uuid.Parse(l)
+				if err != nil {
+					return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+				}
+				filter.LocationID = &parsed
+			}
+			if filter.Attributes, err = attributeFilters(c); err != nil {
+				return utils.ErrResponseWithLog(c, h.logger, err)
+			}
+			equipmentList, err := h.equipmentUC.GetEquipments(c.Request().Context(), paginationQuery, filter)
 			if err != nil {
 				utils.LogResponseError(c, h.logger, err)
 				return c.JSON(httpErrors.ErrorResponse(err))
@@ -169,6 +391,711 @@ func (h *equipmentHandlers) ReserveEquipment() echo.HandlerFunc {
 		if !created {
 			return c.JSON(httpErrors.ErrorResponse(httpErrors.BadRequest))
 		}
-		return c.NoContent(http.StatusCreated)
+		return c.JSON(http.StatusCreated, usersEquipment)
+	}
+}
+
+func (h *equipmentHandlers) GetPendingReservations() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		reservations, err := h.equipmentUC.GetPendingReservations(c.Request().Context())
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, reservations)
+	}
+}
+
+func (h *equipmentHandlers) ApproveReservation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rID, err := strconv.Atoi(c.Param("reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.equipmentUC.ApproveReservation(c.Request().Context(), rID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) RejectReservation() echo.HandlerFunc {
+	type Rejection struct {
+		Reason string `json:"reason" validate:"required,lte=500"`
+	}
+	return func(c echo.Context) error {
+		rID, err := strconv.Atoi(c.Param("reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		rejection := &Rejection{}
+		if err := utils.ReadRequest(c, rejection); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		if err = h.equipmentUC.RejectReservation(c.Request().Context(), rID, rejection.Reason); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) CancelReservation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rID, err := strconv.Atoi(c.Param("reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.equipmentUC.CancelReservation(c.Request().Context(), rID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) ShortenReservation() echo.HandlerFunc {
+	type Shortening struct {
+		ReservationEnd time.Time `json:"reservation_end" validate:"required"`
+	}
+	return func(c echo.Context) error {
+		rID, err := strconv.Atoi(c.Param("reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		shortening := &Shortening{}
+		if err := utils.ReadRequest(c, shortening); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		if err = h.equipmentUC.ShortenReservation(c.Request().Context(), rID, shortening.ReservationEnd); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) JoinWaitlist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		entry := &models.WaitlistEntry{}
+		if err := utils.ReadRequest(c, entry); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		created, err := h.equipmentUC.JoinWaitlist(c.Request().Context(), entry)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *equipmentHandlers) LeaveWaitlist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		entryID, err := strconv.Atoi(c.Param("entry_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.equipmentUC.LeaveWaitlist(c.Request().Context(), entryID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// Queue of the equipment given in equipment_id query param, own entries otherwise
+func (h *equipmentHandlers) GetWaitlist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idStr := c.QueryParam("equipment_id")
+		if idStr == "" {
+			entries, err := h.equipmentUC.GetUserWaitlist(c.Request().Context())
+			if err != nil {
+				return utils.ErrResponseWithLog(c, h.logger, err)
+			}
+			return c.JSON(http.StatusOK, entries)
+		}
+
+		eID, err := uuid.Parse(idStr)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+		entries, err := h.equipmentUC.GetEquipmentWaitlist(c.Request().Context(), eID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, entries)
+	}
+}
+
+type reservationWindow struct {
+	ReservationStart time.Time `json:"reservation_start" validate:"required"`
+	ReservationEnd   time.Time `json:"reservation_end" validate:"required"`
+}
+
+type conflictResponse struct {
+	Status    int                     `json:"status"`
+	Error     string                  `json:"error"`
+	Conflicts []models.UsersEquipment `json:"conflicts"`
+}
+
+func conflictsJSON(c echo.Context, conflicts []models.UsersEquipment) error {
+	return c.JSON(http.StatusConflict, conflictResponse{
+		Status:    http.StatusConflict,
+		Error:     httpErrors.ErrReservationConflict,
+		Conflicts: conflicts,
+	})
+}
+
+func (h *equipmentHandlers) RescheduleReservation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rID, err := strconv.Atoi(c.Param("reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		window := &reservationWindow{}
+		if err := utils.ReadRequest(c, window); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		conflicts, err := h.equipmentUC.RescheduleReservation(
+			c.Request().Context(), rID, window.ReservationStart, window.ReservationEnd,
+		)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		if len(conflicts) > 0 {
+			return conflictsJSON(c, conflicts)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) CreateSeries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		series := &models.ReservationSeries{}
+		if err := utils.ReadRequest(c, series); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		created, conflicts, err := h.equipmentUC.CreateSeries(c.Request().Context(), series)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		if len(conflicts) > 0 {
+			return conflictsJSON(c, conflicts)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *equipmentHandlers) GetSeries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		sID, err := uuid.Parse(c.Param("series_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		series, err := h.equipmentUC.GetSeries(c.Request().Context(), sID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, series)
+	}
+}
+
+// Window of the body gives the new time of day and duration of upcoming occurrences
+func (h *equipmentHandlers) UpdateSeries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		sID, err := uuid.Parse(c.Param("series_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		window := &reservationWindow{}
+		if err := utils.ReadRequest(c, window); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		conflicts, err := h.equipmentUC.UpdateSeries(
+			c.Request().Context(), sID, window.ReservationStart, window.ReservationEnd,
+		)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		if len(conflicts) > 0 {
+			return conflictsJSON(c, conflicts)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) CancelSeries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		sID, err := uuid.Parse(c.Param("series_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.equipmentUC.CancelSeries(c.Request().Context(), sID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) CheckOut() echo.HandlerFunc {
+	return h.handover(h.equipmentUC.CheckOut)
+}
+
+func (h *equipmentHandlers) CheckIn() echo.HandlerFunc {
+	return h.handover(h.equipmentUC.CheckIn)
+}
+
+func (h *equipmentHandlers) handover(
+	record func(ctx context.Context, reservationID int, handover *models.Handover) (*models.Handover, error),
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rID, err := strconv.Atoi(c.Param("reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		handover := &models.Handover{}
+		if err := utils.ReadRequest(c, handover); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		created, err := record(c.Request().Context(), rID, handover)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *equipmentHandlers) GetReservationHandovers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rID, err := strconv.Atoi(c.Param("reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		handovers, err := h.equipmentUC.GetReservationHandovers(c.Request().Context(), rID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, handovers)
+	}
+}
+
+func (h *equipmentHandlers) GetEquipmentHandovers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		handovers, err := h.equipmentUC.GetEquipmentHandovers(c.Request().Context(), eID, paginationQuery)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, handovers)
+	}
+}
+
+func (h *equipmentHandlers) GetLocationHistory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		changes, err := h.equipmentUC.GetLocationHistory(c.Request().Context(), eID, paginationQuery)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, changes)
+	}
+}
+
+func (h *equipmentHandlers) SetStatus() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		request := &models.EquipmentStatusRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		equipment, err := h.equipmentUC.SetStatus(c.Request().Context(), eID, request.Status)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, equipment)
+	}
+}
+
+func (h *equipmentHandlers) ScheduleMaintenance() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		maintenance := &models.Maintenance{}
+		if err := utils.ReadRequest(c, maintenance); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		maintenance.EquipmentID = eID
+
+		schedule, err := h.equipmentUC.ScheduleMaintenance(c.Request().Context(), maintenance)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, schedule)
+	}
+}
+
+func (h *equipmentHandlers) GetMaintenance() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		maintenance, err := h.equipmentUC.GetMaintenance(c.Request().Context(), eID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, maintenance)
+	}
+}
+
+func (h *equipmentHandlers) CancelMaintenance() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		mID, err := strconv.Atoi(c.Param("maintenance_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.equipmentUC.CancelMaintenance(c.Request().Context(), eID, mID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) CreateUnit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		unit := &models.EquipmentUnit{}
+		if err := utils.ReadRequest(c, unit); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		unit.EquipmentID = eID
+
+		created, err := h.equipmentUC.CreateUnit(c.Request().Context(), unit)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *equipmentHandlers) UpdateUnit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		uID, err := uuid.Parse(c.Param("unit_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		unit := &models.EquipmentUnit{}
+		if err := utils.ReadRequest(c, unit); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		unit.EquipmentID = eID
+		unit.UnitID = uID
+
+		if err = h.equipmentUC.UpdateUnit(c.Request().Context(), unit); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) DeleteUnit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		uID, err := uuid.Parse(c.Param("unit_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.equipmentUC.DeleteUnit(c.Request().Context(), eID, uID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) GetUnits() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eID, err := uuid.Parse(c.Param("equipment_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		units, err := h.equipmentUC.GetUnits(c.Request().Context(), eID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, units)
+	}
+}
+
+func (h *equipmentHandlers) CreateKit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		kit := &models.Kit{}
+		if err := utils.ReadRequest(c, kit); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		created, err := h.equipmentUC.CreateKit(c.Request().Context(), kit)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *equipmentHandlers) UpdateKit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		kID, err := uuid.Parse(c.Param("kit_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+		kit := &models.Kit{}
+		if err := utils.ReadRequest(c, kit); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		kit.KitID = kID
+
+		if err = h.equipmentUC.UpdateKit(c.Request().Context(), kit); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) DeleteKit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		kID, err := uuid.Parse(c.Param("kit_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.equipmentUC.DeleteKit(c.Request().Context(), kID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) GetKit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		kID, err := uuid.Parse(c.Param("kit_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		kit, err := h.equipmentUC.GetKit(c.Request().Context(), kID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, kit)
+	}
+}
+
+func (h *equipmentHandlers) GetKits() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		kits, err := h.equipmentUC.GetKits(c.Request().Context())
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, kits)
+	}
+}
+
+// Books every component of the kit for the window of the body, on a collision nothing
+// is booked and the colliding components are listed
+func (h *equipmentHandlers) ReserveKit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		kID, err := uuid.Parse(c.Param("kit_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		window := &reservationWindow{}
+		if err := utils.ReadRequest(c, window); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		created, conflicts, err := h.equipmentUC.ReserveKit(c.Request().Context(), &models.KitReservation{
+			KitID:            kID,
+			ReservationStart: window.ReservationStart,
+			ReservationEnd:   window.ReservationEnd,
+		})
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		if len(conflicts) > 0 {
+			return conflictsJSON(c, conflicts)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *equipmentHandlers) GetKitReservation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		krID, err := uuid.Parse(c.Param("kit_reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		kr, err := h.equipmentUC.GetKitReservation(c.Request().Context(), krID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, kr)
+	}
+}
+
+func (h *equipmentHandlers) CancelKitReservation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		krID, err := uuid.Parse(c.Param("kit_reservation_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.equipmentUC.CancelKitReservation(c.Request().Context(), krID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) SetEquipmentType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		equipmentType := &models.EquipmentType{}
+		if err := utils.ReadRequest(c, equipmentType); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		equipmentType.Type = c.Param("type")
+
+		if err := h.equipmentUC.SetEquipmentType(c.Request().Context(), equipmentType); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, equipmentType)
+	}
+}
+
+func (h *equipmentHandlers) DeleteEquipmentType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := h.equipmentUC.DeleteEquipmentType(c.Request().Context(), c.Param("type")); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *equipmentHandlers) GetEquipmentType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		equipmentType, err := h.equipmentUC.GetEquipmentType(c.Request().Context(), c.Param("type"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, equipmentType)
+	}
+}
+
+func (h *equipmentHandlers) GetEquipmentTypes() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		types, err := h.equipmentUC.GetEquipmentTypes(c.Request().Context())
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, types)
 	}
 }
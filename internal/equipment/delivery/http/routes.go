@@ -3,17 +3,69 @@ package http
 import (
 	"equiptrack/internal/equipment"
 	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
 
 	"github.com/labstack/echo/v4"
 )
 
 func MapEquipmentRoutes(equipGroup *echo.Group, h equipment.Handlers, mw *middleware.MiddlewareManager) {
+	read := mw.RequireScope(models.ScopeEquipmentRead)
+	write := mw.RequireScope(models.ScopeEquipmentWrite)
+	reserve := mw.RequireScope(models.ScopeReservationsWrite)
+
 	equipGroup.Use(mw.AuthJWTMiddleware)
-	equipGroup.POST("/create", h.Create(), mw.IsAdminMiddleware)
-	equipGroup.POST("/reserve", h.ReserveEquipment())
-	equipGroup.GET("/reservations_info/:equipment_id", h.GetReservationInfo())
-	equipGroup.DELETE("/:equipment_id", h.Delete(), mw.IsAdminMiddleware)
-	equipGroup.PUT("/update", h.Update(), mw.IsAdminMiddleware)
-	equipGroup.GET("/:equipment_id", h.GetByID())
-	equipGroup.GET("", h.GetEquipments())
+	equipGroup.POST("/create", h.Create(), mw.IsAdminMiddleware, write)
+	equipGroup.POST("/reserve", h.ReserveEquipment(), reserve)
+	equipGroup.GET("/reservations/pending", h.GetPendingReservations(), read)
+	equipGroup.POST("/reservations/:reservation_id/approve", h.ApproveReservation(), reserve)
+	equipGroup.POST("/reservations/:reservation_id/reject", h.RejectReservation(), reserve)
+	equipGroup.POST("/reservations/:reservation_id/cancel", h.CancelReservation(), reserve)
+	equipGroup.POST("/reservations/:reservation_id/shorten", h.ShortenReservation(), reserve)
+	equipGroup.PUT("/reservations/:reservation_id", h.RescheduleReservation(), reserve)
+	equipGroup.POST("/reservations/series", h.CreateSeries(), reserve)
+	equipGroup.GET("/reservations/series/:series_id", h.GetSeries(), read)
+	equipGroup.PUT("/reservations/series/:series_id", h.UpdateSeries(), reserve)
+	equipGroup.DELETE("/reservations/series/:series_id", h.CancelSeries(), reserve)
+	equipGroup.POST("/reservations/:reservation_id/check_out", h.CheckOut(), reserve)
+	equipGroup.POST("/reservations/:reservation_id/check_in", h.CheckIn(), reserve)
+	equipGroup.GET("/reservations/:reservation_id/handovers", h.GetReservationHandovers(), read)
+	equipGroup.POST("/waitlist", h.JoinWaitlist(), reserve)
+	equipGroup.GET("/waitlist", h.GetWaitlist(), read)
+	equipGroup.DELETE("/waitlist/:entry_id", h.LeaveWaitlist(), reserve)
+	equipGroup.POST("/kits", h.CreateKit(), mw.IsAdminMiddleware, write)
+	equipGroup.GET("/kits", h.GetKits(), read)
+	equipGroup.GET("/kits/:kit_id", h.GetKit(), read)
+	equipGroup.PUT("/kits/:kit_id", h.UpdateKit(), mw.IsAdminMiddleware, write)
+	equipGroup.DELETE("/kits/:kit_id", h.DeleteKit(), mw.IsAdminMiddleware, write)
+	equipGroup.POST("/kits/:kit_id/reserve", h.ReserveKit(), reserve)
+	equipGroup.GET("/kits/reservations/:kit_reservation_id", h.GetKitReservation(), read)
+	equipGroup.POST("/kits/reservations/:kit_reservation_id/cancel", h.CancelKitReservation(), reserve)
+	equipGroup.GET("/types", h.GetEquipmentTypes(), read)
+	equipGroup.GET("/types/:type", h.GetEquipmentType(), read)
+	equipGroup.PUT("/types/:type", h.SetEquipmentType(), mw.IsAdminMiddleware, write)
+	equipGroup.DELETE("/types/:type", h.DeleteEquipmentType(), mw.IsAdminMiddleware, write)
+	equipGroup.GET("/reservations_info/:equipment_id", h.GetReservationInfo(), read)
+	equipGroup.GET("/archived", h.GetArchivedEquipments(), mw.IsAdminMiddleware, read)
+	equipGroup.GET("/lookup", h.Lookup(), read)
+	equipGroup.GET("/:equipment_id/qr", h.GetQRCode(), read)
+	equipGroup.POST("/:equipment_id/attachments", h.UploadAttachment(), mw.IsAdminMiddleware, write)
+	equipGroup.GET("/:equipment_id/attachments/:attachment_id", h.GetAttachment(), read)
+	equipGroup.DELETE("/:equipment_id/attachments/:attachment_id", h.DeleteAttachment(), mw.IsAdminMiddleware, write)
+	equipGroup.GET("/labels/templates", h.GetLabelTemplates(), mw.IsAdminMiddleware, read)
+	equipGroup.POST("/labels", h.GetLabelSheet(), mw.IsAdminMiddleware, read)
+	equipGroup.DELETE("/:equipment_id", h.Delete(), mw.IsAdminMiddleware, write)
+	equipGroup.POST("/:equipment_id/restore", h.Restore(), mw.IsAdminMiddleware, write)
+	equipGroup.PUT("/update", h.Update(), mw.IsAdminMiddleware, write)
+	equipGroup.GET("/:equipment_id/handovers", h.GetEquipmentHandovers(), read)
+	equipGroup.GET("/:equipment_id/locations", h.GetLocationHistory(), read)
+	equipGroup.PUT("/:equipment_id/status", h.SetStatus(), mw.IsAdminMiddleware, write)
+	equipGroup.POST("/:equipment_id/maintenance", h.ScheduleMaintenance(), mw.IsAdminMiddleware, write)
+	equipGroup.GET("/:equipment_id/maintenance", h.GetMaintenance(), read)
+	equipGroup.DELETE("/:equipment_id/maintenance/:maintenance_id", h.CancelMaintenance(), mw.IsAdminMiddleware, write)
+	equipGroup.POST("/:equipment_id/units", h.CreateUnit(), mw.IsAdminMiddleware, write)
+	equipGroup.GET("/:equipment_id/units", h.GetUnits(), read)
+	equipGroup.PUT("/:equipment_id/units/:unit_id", h.UpdateUnit(), mw.IsAdminMiddleware, write)
+	equipGroup.DELETE("/:equipment_id/units/:unit_id", h.DeleteUnit(), mw.IsAdminMiddleware, write)
+	equipGroup.GET("/:equipment_id", h.GetByID(), read)
+	equipGroup.GET("", h.GetEquipments(), read)
 }
@@ -0,0 +1,48 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	DefaultSize = 256
+	MaxSize     = 2048
+)
+
+// Medium error correction survives a scratched or partly covered label
+const recoveryLevel = qrcode.Medium
+
+// PNG renders content as a square QR code of size pixels
+func PNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, recoveryLevel, size)
+}
+
+// SVG renders content as a QR code scaled to size, one path covers all dark modules
+func SVG(content string, size int) ([]byte, error) {
+	q, err := qrcode.New(content, recoveryLevel)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := q.Bitmap()
+	n := len(bitmap)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, n, n)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, n, n)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.Bytes(), nil
+}
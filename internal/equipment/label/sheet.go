@@ -0,0 +1,195 @@
+package label
+
+import (
+	"bytes"
+	"equiptrack/config"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Layouts of common adhesive label stock
+var builtinTemplates = []config.LabelTemplate{
+	// A4, 21 labels of 63.5 x 38.1 mm
+	{Name: "avery-l7160", PageWidth: 210, PageHeight: 297, Columns: 3, Rows: 7,
+		LabelWidth: 63.5, LabelHeight: 38.1, MarginTop: 15.1, MarginLeft: 7.2, GapX: 2.5},
+	// A4, 14 labels of 99.1 x 38.1 mm
+	{Name: "avery-l7163", PageWidth: 210, PageHeight: 297, Columns: 2, Rows: 7,
+		LabelWidth: 99.1, LabelHeight: 38.1, MarginTop: 15.1, MarginLeft: 4.7, GapX: 2.5},
+	// US Letter, 30 labels of 2.625 x 1 in
+	{Name: "avery-5160", PageWidth: 215.9, PageHeight: 279.4, Columns: 3, Rows: 10,
+		LabelWidth: 66.7, LabelHeight: 25.4, MarginTop: 12.7, MarginLeft: 4.8, GapX: 3.2},
+}
+
+const (
+	DefaultTemplate = "avery-l7160"
+
+	// Inner padding of a label
+	padding = 2.0
+	// PNG resolution of QR codes embedded in the sheet
+	qrPixels = 300
+)
+
+// Single label, URL is encoded in its QR code
+type Item struct {
+	Name string
+	Tag  string
+	URL  string
+}
+
+// Templates returns built in templates merged with the configured ones, ordered by name
+func Templates(cfg *config.Config) []config.LabelTemplate {
+	byName := make(map[string]config.LabelTemplate)
+	for _, t := range builtinTemplates {
+		byName[t.Name] = t
+	}
+	for _, t := range cfg.Assets.LabelTemplates {
+		byName[t.Name] = t
+	}
+
+	templates := make([]config.LabelTemplate, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// FindTemplate looks a template up by name, empty name selects the default one
+func FindTemplate(cfg *config.Config, name string) (config.LabelTemplate, bool) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	for _, t := range Templates(cfg) {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return config.LabelTemplate{}, false
+}
+
+// Validate reports layouts which don't fit on their page
+func Validate(t config.LabelTemplate) error {
+	if t.Columns < 1 || t.Rows < 1 || t.LabelWidth <= 0 || t.LabelHeight <= 0 {
+		return fmt.Errorf("label template %s: columns, rows and label size must be positive", t.Name)
+	}
+	width := t.MarginLeft + float64(t.Columns)*t.LabelWidth + float64(t.Columns-1)*t.GapX
+	height := t.MarginTop + float64(t.Rows)*t.LabelHeight + float64(t.Rows-1)*t.GapY
+	if width > t.PageWidth+0.01 || height > t.PageHeight+0.01 {
+		return fmt.Errorf("label template %s: labels don't fit on the page", t.Name)
+	}
+	return nil
+}
+
+// Sheet renders the items as a PDF laid out by the template. The first skip positions
+// are left empty so partly used sheets can be printed on again
+func Sheet(t config.LabelTemplate, items []Item, skip int) ([]byte, error) {
+	if err := Validate(t); err != nil {
+		return nil, err
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "mm",
+		Size:    fpdf.SizeType{Wd: t.PageWidth, Ht: t.PageHeight},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetCreator("equiptrack", true)
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	perPage := t.Columns * t.Rows
+	for i, item := range items {
+		pos := i + skip
+		if i == 0 || pos%perPage == 0 {
+			pdf.AddPage()
+		}
+		col := pos % perPage % t.Columns
+		row := pos % perPage / t.Columns
+		x := t.MarginLeft + float64(col)*(t.LabelWidth+t.GapX)
+		y := t.MarginTop + float64(row)*(t.LabelHeight+t.GapY)
+
+		if err := drawLabel(pdf, translate, x, y, t.LabelWidth, t.LabelHeight, item, i); err != nil {
+			return nil, err
+		}
+	}
+	if len(items) == 0 {
+		pdf.AddPage()
+	}
+
+	var b bytes.Buffer
+	if err := pdf.Output(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// QR code on the left filling the label height, name and asset tag next to it
+func drawLabel(pdf *fpdf.Fpdf, translate func(string) string, x, y, w, h float64, item Item, n int) error {
+	qrSide := math.Min(h, w/2) - 2*padding
+	png, err := PNG(item.URL, qrPixels)
+	if err != nil {
+		return err
+	}
+	imageName := fmt.Sprintf("qr%d", n)
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader(imageName, options, bytes.NewReader(png))
+	pdf.ImageOptions(imageName, x+padding, y+padding, qrSide, qrSide, false, options, 0, "")
+
+	textX := x + qrSide + 2*padding
+	textW := w - qrSide - 3*padding
+	pdf.ClipRect(textX, y+padding, textW, h-2*padding, false)
+
+	fontSize := math.Min(10, h/4*72/25.4)
+	lineH := fontSize * 25.4 / 72 * 1.2
+	pdf.SetFont("Helvetica", "B", fontSize)
+	pdf.SetXY(textX, y+padding)
+	for _, line := range wrap(pdf, translate(item.Name), textW, 2) {
+		pdf.CellFormat(textW, lineH, line, "", 2, "L", false, 0, "")
+	}
+
+	pdf.SetFont("Courier", "B", fontSize)
+	pdf.SetXY(textX, y+h-padding-lineH)
+	pdf.CellFormat(textW, lineH, translate(item.Tag), "", 0, "L", false, 0, "")
+	pdf.ClipEnd()
+
+	return pdf.Error()
+}
+
+// Breaks text at spaces into at most max lines fitting width, words longer than
+// a line are cut. Text is measured in the single byte encoding of the core fonts
+func wrap(pdf *fpdf.Fpdf, text string, width float64, max int) []string {
+	lines := make([]string, 0, max)
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if pdf.GetStringWidth(candidate) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = word
+		for pdf.GetStringWidth(line) > width && len(line) > 1 && len(lines) < max {
+			cut := len(line) - 1
+			for cut > 1 && pdf.GetStringWidth(line[:cut]) > width {
+				cut--
+			}
+			lines = append(lines, line[:cut])
+			line = line[cut:]
+		}
+		if len(lines) >= max {
+			return lines[:max]
+		}
+	}
+	if line != "" && len(lines) < max {
+		lines = append(lines, line)
+	}
+	return lines
+}
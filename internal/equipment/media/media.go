@@ -0,0 +1,100 @@
+package media
+
+import (
+	"bytes"
+	"equiptrack/internal/models"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+
+	// decoders for thumbnails of uploaded photos
+	_ "image/gif"
+	_ "image/png"
+)
+
+// Bytes needed by Sniff
+const SniffLength = 512
+
+// Thumbnails aren't generated for images above this many pixels, decoding one takes
+// up to 64 MB
+const maxThumbnailPixels = 16_000_000
+
+// Accepted upload types by their sniffed content type
+var allowed = map[string]string{
+	"image/jpeg":      models.AttachmentPhoto,
+	"image/png":       models.AttachmentPhoto,
+	"image/gif":       models.AttachmentPhoto,
+	"image/webp":      models.AttachmentPhoto,
+	"application/pdf": models.AttachmentDocument,
+}
+
+// Sniff detects the content type from the first bytes of the file, the name and the
+// declared type are not trusted. Returns false for types which may not be uploaded
+func Sniff(head []byte) (contentType string, kind string, ok bool) {
+	contentType = http.DetectContentType(head)
+	kind, ok = allowed[contentType]
+	return contentType, kind, ok
+}
+
+// Thumbnail downscales the image to fit into a size x size square and encodes it as JPEG.
+// Images which can't be decoded, like WebP, or are too large return false
+func Thumbnail(r io.ReadSeeker, size int) ([]byte, bool, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil || cfg.Width*cfg.Height > maxThumbnailPixels {
+		return nil, false, nil
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return nil, false, err
+	}
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	var b bytes.Buffer
+	if err = jpeg.Encode(&b, scale(src, size), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, false, err
+	}
+	return b.Bytes(), true, nil
+}
+
+// Box filter downscaling keeping the aspect ratio, smaller images are only flattened
+func scale(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := bounds.Min.Y+y*h/th, bounds.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := bounds.Min.X+x*w/tw, bounds.Min.X+(x+1)*w/tw
+			var r, g, b, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			// transparent areas end up white in the JPEG
+			alpha := a / n
+			white := 0xffff - alpha
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r/n + white),
+				G: uint16(g/n + white),
+				B: uint16(b/n + white),
+				A: 0xffff,
+			})
+		}
+	}
+	return dst
+}
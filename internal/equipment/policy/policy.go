@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"equiptrack/config"
+	"equiptrack/internal/models"
+	"fmt"
+	"time"
+)
+
+const (
+	RuleMaxDuration      = "max_duration"
+	RuleMinAdvance       = "min_advance"
+	RuleMaxAdvance       = "max_advance"
+	RuleMaxConcurrent    = "max_concurrent"
+	RuleMaxHoursPerMonth = "max_hours_per_month"
+)
+
+// Reservation accepted earlier in the same request which isn't stored yet, so stored
+// usage doesn't include it
+type Pending struct {
+	Reservation   models.UsersEquipment
+	EquipmentType string
+}
+
+// Violation names the policy and the rule a reservation breaks
+type Violation struct {
+	Policy  string `json:"policy"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("booking policy %q violated, %s: %s", v.Policy, v.Rule, v.Message)
+}
+
+type Engine struct {
+	policies []config.BookingPolicy
+}
+
+func NewEngine(cfg *config.Config) *Engine {
+	return &Engine{policies: cfg.Booking.Policies}
+}
+
+// Policies applying to the user booking the equipment
+func (e *Engine) Applicable(user *models.User, equipment *models.Equipment) []config.BookingPolicy {
+	policies := make([]config.BookingPolicy, 0, len(e.policies))
+	for _, p := range e.policies {
+		if p.EquipmentType != "" && p.EquipmentType != equipment.Type {
+			continue
+		}
+		if p.Role != "" && p.Role != user.Role {
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies
+}
+
+// Check reservation against a single policy, usage must not include the reservation itself
+func (e *Engine) Check(
+	p config.BookingPolicy,
+	reservation *models.UsersEquipment,
+	usage *models.BookingUsage,
+	now time.Time,
+) error {
+	duration := reservation.ReservationEnd.Sub(reservation.ReservationStart)
+	advance := reservation.ReservationStart.Sub(now)
+
+	if limit := p.MaxDuration * time.Hour; limit > 0 && duration > limit {
+		return violation(p, RuleMaxDuration, "reservation lasts %s, at most %s allowed", duration, limit)
+	}
+	if limit := p.MinAdvance * time.Hour; limit > 0 && advance < limit {
+		return violation(p, RuleMinAdvance, "reservation must be made at least %s in advance", limit)
+	}
+	if limit := p.MaxAdvance * time.Hour; limit > 0 && advance > limit {
+		return violation(p, RuleMaxAdvance, "reservation can be made at most %s in advance", limit)
+	}
+	if p.MaxConcurrent > 0 && usage.ActiveReservations+1 > p.MaxConcurrent {
+		return violation(p, RuleMaxConcurrent, "at most %d active reservations allowed", p.MaxConcurrent)
+	}
+	if p.MaxHoursPerMonth > 0 && usage.HoursInMonth+duration.Hours() > p.MaxHoursPerMonth {
+		return violation(p, RuleMaxHoursPerMonth,
+			"%.1f hours already booked this month, at most %.1f allowed", usage.HoursInMonth, p.MaxHoursPerMonth)
+	}
+	return nil
+}
+
+func violation(p config.BookingPolicy, rule string, format string, args ...interface{}) *Violation {
+	return &Violation{Policy: p.Name, Rule: rule, Message: fmt.Sprintf(format, args...)}
+}
+
+// Month boundaries used for the monthly hours limit
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// Usage without an existing reservation, used when that reservation is being replaced
+func Without(usage models.BookingUsage, reservation *models.UsersEquipment, monthStart time.Time, now time.Time) models.BookingUsage {
+	if reservation.ReservationEnd.After(now) && usage.ActiveReservations > 0 {
+		usage.ActiveReservations--
+	}
+	start := reservation.ReservationStart
+	if !start.Before(monthStart) && start.Before(monthStart.AddDate(0, 1, 0)) {
+		usage.HoursInMonth -= reservation.ReservationEnd.Sub(start).Hours()
+	}
+	return usage
+}
+
+// Usage with reservations which aren't stored yet added. Type specific usage only counts
+// pending reservations of equipment of that type
+func With(usage models.BookingUsage, pending []Pending, equipmentType string, monthStart time.Time, now time.Time) models.BookingUsage {
+	for _, pend := range pending {
+		if equipmentType != "" && pend.EquipmentType != equipmentType {
+			continue
+		}
+		reservation := pend.Reservation
+		if reservation.ReservationEnd.After(now) {
+			usage.ActiveReservations++
+		}
+		start := reservation.ReservationStart
+		if !start.Before(monthStart) && start.Before(monthStart.AddDate(0, 1, 0)) {
+			usage.HoursInMonth += reservation.ReservationEnd.Sub(start).Hours()
+		}
+	}
+	return usage
+}
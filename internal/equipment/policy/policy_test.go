@@ -0,0 +1,191 @@
+package policy
+
+import (
+	"equiptrack/config"
+	"equiptrack/internal/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func reservation(start time.Time, hours int) *models.UsersEquipment {
+	return &models.UsersEquipment{ReservationStart: start, ReservationEnd: start.Add(time.Duration(hours) * time.Hour)}
+}
+
+func TestCheck(t *testing.T) {
+	tomorrow := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		policy      config.BookingPolicy
+		reservation *models.UsersEquipment
+		usage       models.BookingUsage
+		wantRule    string
+	}{
+		{name: "no limits", policy: config.BookingPolicy{}, reservation: reservation(tomorrow, 1000),
+			usage: models.BookingUsage{ActiveReservations: 100, HoursInMonth: 1000}},
+		{name: "duration at the limit", policy: config.BookingPolicy{MaxDuration: 8},
+			reservation: reservation(tomorrow, 8)},
+		{name: "duration above the limit", policy: config.BookingPolicy{MaxDuration: 8},
+			reservation: reservation(tomorrow, 9), wantRule: RuleMaxDuration},
+		{name: "advance at the minimum", policy: config.BookingPolicy{MinAdvance: 24},
+			reservation: reservation(tomorrow, 1)},
+		{name: "advance below the minimum", policy: config.BookingPolicy{MinAdvance: 48},
+			reservation: reservation(tomorrow, 1), wantRule: RuleMinAdvance},
+		{name: "advance at the maximum", policy: config.BookingPolicy{MaxAdvance: 24},
+			reservation: reservation(tomorrow, 1)},
+		{name: "advance above the maximum", policy: config.BookingPolicy{MaxAdvance: 12},
+			reservation: reservation(tomorrow, 1), wantRule: RuleMaxAdvance},
+		{name: "concurrent below the limit", policy: config.BookingPolicy{MaxConcurrent: 2},
+			reservation: reservation(tomorrow, 1), usage: models.BookingUsage{ActiveReservations: 1}},
+		{name: "concurrent at the limit", policy: config.BookingPolicy{MaxConcurrent: 2},
+			reservation: reservation(tomorrow, 1), usage: models.BookingUsage{ActiveReservations: 2},
+			wantRule: RuleMaxConcurrent},
+		{name: "monthly hours filled exactly", policy: config.BookingPolicy{MaxHoursPerMonth: 10},
+			reservation: reservation(tomorrow, 4), usage: models.BookingUsage{HoursInMonth: 6}},
+		{name: "monthly hours exceeded", policy: config.BookingPolicy{MaxHoursPerMonth: 10},
+			reservation: reservation(tomorrow, 4), usage: models.BookingUsage{HoursInMonth: 6.5},
+			wantRule: RuleMaxHoursPerMonth},
+		{name: "first broken rule is reported", policy: config.BookingPolicy{MaxDuration: 1, MaxConcurrent: 1},
+			reservation: reservation(tomorrow, 2), usage: models.BookingUsage{ActiveReservations: 1},
+			wantRule: RuleMaxDuration},
+	}
+
+	engine := &Engine{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.Name = "test"
+			err := engine.Check(tt.policy, tt.reservation, &tt.usage, now)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+				return
+			}
+			var v *Violation
+			if !errors.As(err, &v) {
+				t.Fatalf("Check() error = %v, want violation of %s", err, tt.wantRule)
+			}
+			if v.Rule != tt.wantRule || v.Policy != "test" {
+				t.Errorf("Check() violated %s of %s, want %s of test", v.Rule, v.Policy, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestApplicable(t *testing.T) {
+	engine := NewEngine(&config.Config{Booking: config.BookingConfig{Policies: []config.BookingPolicy{
+		{Name: "everyone"},
+		{Name: "laptops", EquipmentType: "laptop"},
+		{Name: "students", Role: "student"},
+		{Name: "student laptops", EquipmentType: "laptop", Role: "student"},
+	}}})
+
+	tests := []struct {
+		role string
+		typ  string
+		want []string
+	}{
+		{role: "student", typ: "laptop", want: []string{"everyone", "laptops", "students", "student laptops"}},
+		{role: "student", typ: "camera", want: []string{"everyone", "students"}},
+		{role: "staff", typ: "laptop", want: []string{"everyone", "laptops"}},
+		{role: "staff", typ: "", want: []string{"everyone"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role+" "+tt.typ, func(t *testing.T) {
+			got := engine.Applicable(&models.User{Role: tt.role}, &models.Equipment{Type: tt.typ})
+			if len(got) != len(tt.want) {
+				t.Fatalf("Applicable() = %v, want %v", got, tt.want)
+			}
+			for i, p := range got {
+				if p.Name != tt.want[i] {
+					t.Errorf("policy %d is %s, want %s", i, p.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMonthStart(t *testing.T) {
+	local := time.FixedZone("UTC+3", 3*60*60)
+	got := MonthStart(time.Date(2024, 3, 31, 23, 59, 0, 0, local))
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, local); !got.Equal(want) || got.Location() != local {
+		t.Errorf("MonthStart() = %s, want %s", got, want)
+	}
+}
+
+func TestWithout(t *testing.T) {
+	monthStart := MonthStart(now)
+	usage := models.BookingUsage{ActiveReservations: 2, HoursInMonth: 20}
+
+	tests := []struct {
+		name        string
+		usage       models.BookingUsage
+		reservation *models.UsersEquipment
+		want        models.BookingUsage
+	}{
+		{name: "active this month", usage: usage, reservation: reservation(now.Add(time.Hour), 5),
+			want: models.BookingUsage{ActiveReservations: 1, HoursInMonth: 15}},
+		{name: "starting on the first of the month", usage: usage, reservation: reservation(monthStart, 5),
+			want: models.BookingUsage{ActiveReservations: 2, HoursInMonth: 15}},
+		{name: "ended earlier this month", usage: usage, reservation: reservation(now.Add(-10*time.Hour), 5),
+			want: models.BookingUsage{ActiveReservations: 2, HoursInMonth: 15}},
+		{name: "starting last month", usage: usage, reservation: reservation(monthStart.Add(-2*time.Hour), 5),
+			want: models.BookingUsage{ActiveReservations: 2, HoursInMonth: 20}},
+		{name: "starting next month", usage: usage, reservation: reservation(monthStart.AddDate(0, 1, 0), 5),
+			want: models.BookingUsage{ActiveReservations: 1, HoursInMonth: 20}},
+		{name: "no active reservations left", usage: models.BookingUsage{}, reservation: reservation(now.Add(time.Hour), 5),
+			want: models.BookingUsage{HoursInMonth: -5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Without(tt.usage, tt.reservation, monthStart, now); got != tt.want {
+				t.Errorf("Without() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWith(t *testing.T) {
+	monthStart := MonthStart(now)
+	pending := func(typ string, r *models.UsersEquipment) Pending {
+		return Pending{Reservation: *r, EquipmentType: typ}
+	}
+	usage := models.BookingUsage{ActiveReservations: 1, HoursInMonth: 10}
+
+	tests := []struct {
+		name    string
+		pending []Pending
+		typ     string
+		want    models.BookingUsage
+	}{
+		{name: "nothing pending", want: usage},
+		{name: "upcoming this month", pending: []Pending{pending("laptop", reservation(now.Add(time.Hour), 3))},
+			want: models.BookingUsage{ActiveReservations: 2, HoursInMonth: 13}},
+		{name: "starting on the first of the month", pending: []Pending{pending("laptop", reservation(monthStart, 3))},
+			want: models.BookingUsage{ActiveReservations: 1, HoursInMonth: 13}},
+		{name: "starting at the end of the month", typ: "laptop",
+			pending: []Pending{pending("laptop", reservation(monthStart.AddDate(0, 1, 0).Add(-time.Hour), 3))},
+			want:    models.BookingUsage{ActiveReservations: 2, HoursInMonth: 13}},
+		{name: "starting next month", pending: []Pending{pending("laptop", reservation(monthStart.AddDate(0, 1, 0), 3))},
+			want: models.BookingUsage{ActiveReservations: 2, HoursInMonth: 10}},
+		{name: "several accumulate", pending: []Pending{
+			pending("laptop", reservation(now.Add(time.Hour), 3)),
+			pending("camera", reservation(now.Add(48*time.Hour), 2)),
+			pending("laptop", reservation(monthStart.AddDate(0, 2, 0), 4)),
+		}, want: models.BookingUsage{ActiveReservations: 4, HoursInMonth: 15}},
+		{name: "type specific usage skips other types", typ: "laptop", pending: []Pending{
+			pending("laptop", reservation(now.Add(time.Hour), 3)),
+			pending("camera", reservation(now.Add(48*time.Hour), 2)),
+		}, want: models.BookingUsage{ActiveReservations: 2, HoursInMonth: 13}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := With(usage, tt.pending, tt.typ, monthStart, now); got != tt.want {
+				t.Errorf("With() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
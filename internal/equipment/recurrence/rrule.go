@@ -0,0 +1,194 @@
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+
+	// Upper bound of occurrences in one series
+	MaxOccurrences = 366
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Subset of RFC 5545 RRULE: FREQ, INTERVAL, BYDAY, COUNT and UNTIL.
+// Either COUNT or UNTIL is required so every series is finite
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    time.Time
+}
+
+// Parse rule like FREQ=WEEKLY;BYDAY=TU;UNTIL=20241231
+func Parse(rule string) (*Rule, error) {
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"), ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+			if r.Freq != Daily && r.Freq != Weekly && r.Freq != Monthly {
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			if r.Interval, err = strconv.Atoi(value); err != nil || r.Interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+		case "COUNT":
+			if r.Count, err = strconv.Atoi(value); err != nil || r.Count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", value)
+			}
+		case "UNTIL":
+			if r.Until, err = parseUntil(value); err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", value)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", day)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if r.Count == 0 && r.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT or UNTIL is required")
+	}
+	if r.Count > MaxOccurrences {
+		return nil, fmt.Errorf("at most %d occurrences allowed", MaxOccurrences)
+	}
+	if r.Freq == Monthly && len(r.ByDay) > 0 {
+		return nil, fmt.Errorf("BYDAY is not supported with MONTHLY")
+	}
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// date only UNTIL includes the whole day
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// Start times of the occurrences at or after start
+func (r *Rule) Occurrences(start time.Time) ([]time.Time, error) {
+	occurrences := make([]time.Time, 0)
+	add := func(t time.Time) bool {
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		if r.Count > 0 && len(occurrences) >= r.Count {
+			return false
+		}
+		occurrences = append(occurrences, t)
+		return true
+	}
+
+	y, m, d := start.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	}
+
+	// every period adds at least one candidate, so this bounds the loop even when
+	// BYDAY filters everything out
+	for period := 0; period <= MaxOccurrences*7; period++ {
+		var candidates []time.Time
+		switch r.Freq {
+		case Daily:
+			t := at(y, m, d+period*r.Interval)
+			if r.matchesDay(t) {
+				candidates = append(candidates, t)
+			} else if !r.Until.IsZero() && t.After(r.Until) {
+				return r.checked(occurrences)
+			}
+		case Weekly:
+			monday := d - (int(start.Weekday())+6)%7 + period*7*r.Interval
+			days := r.ByDay
+			if len(days) == 0 {
+				days = []time.Weekday{start.Weekday()}
+			}
+			for offset := 0; offset < 7; offset++ {
+				t := at(y, m, monday+offset)
+				if !t.Before(start) && containsDay(days, t.Weekday()) {
+					candidates = append(candidates, t)
+				}
+			}
+		case Monthly:
+			t := at(y, m+time.Month(period*r.Interval), d)
+			// months without that day are skipped as RFC 5545 requires
+			if t.Day() == d {
+				candidates = append(candidates, t)
+			}
+		}
+
+		for _, t := range candidates {
+			if !add(t) {
+				return r.checked(occurrences)
+			}
+		}
+		if len(occurrences) > MaxOccurrences {
+			break
+		}
+	}
+	return r.checked(occurrences)
+}
+
+func (r *Rule) checked(occurrences []time.Time) ([]time.Time, error) {
+	if len(occurrences) == 0 {
+		return nil, fmt.Errorf("rule produces no occurrences")
+	}
+	if len(occurrences) > MaxOccurrences {
+		return nil, fmt.Errorf("at most %d occurrences allowed", MaxOccurrences)
+	}
+	return occurrences, nil
+}
+
+func (r *Rule) matchesDay(t time.Time) bool {
+	return len(r.ByDay) == 0 || containsDay(r.ByDay, t.Weekday())
+}
+
+func containsDay(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    *Rule
+		wantErr bool
+	}{
+		{
+			rule: "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4",
+			want: &Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Tuesday, time.Thursday}, Count: 4},
+		},
+		{
+			rule: "RRULE:freq=daily;interval=2;until=20240110",
+			want: &Rule{Freq: Daily, Interval: 2, Until: time.Date(2024, 1, 10, 23, 59, 59, 999999999, time.UTC)},
+		},
+		{
+			rule: "FREQ=MONTHLY;UNTIL=20240110T120000Z",
+			want: &Rule{Freq: Monthly, Interval: 1, Until: time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)},
+		},
+		{rule: "FREQ=DAILY;COUNT=366", want: &Rule{Freq: Daily, Interval: 1, Count: 366}},
+		{rule: "FREQ=DAILY;COUNT=367", wantErr: true},
+		{rule: "COUNT=3", wantErr: true},
+		{rule: "FREQ=DAILY", wantErr: true},
+		{rule: "FREQ=YEARLY;COUNT=3", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0;COUNT=3", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=XX;COUNT=3", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYDAY=MO;COUNT=3", wantErr: true},
+		{rule: "FREQ=DAILY;UNTIL=2024-01-10", wantErr: true},
+		{rule: "FREQ=DAILY;BYHOUR=10;COUNT=3", wantErr: true},
+		{rule: "FREQ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := Parse(tt.rule)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.Freq != tt.want.Freq || got.Interval != tt.want.Interval || got.Count != tt.want.Count ||
+				!got.Until.Equal(tt.want.Until) || !sameDays(got.ByDay, tt.want.ByDay) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	// Monday
+	monday := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	wednesday := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)
+	lastOfJanuary := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rule    string
+		start   time.Time
+		want    []string
+		wantErr bool
+	}{
+		{name: "daily count", rule: "FREQ=DAILY;COUNT=3", start: monday, want: []string{"01-01", "01-02", "01-03"}},
+		{name: "daily until includes the whole day", rule: "FREQ=DAILY;UNTIL=20240103", start: monday,
+			want: []string{"01-01", "01-02", "01-03"}},
+		{name: "until before the first time of day", rule: "FREQ=DAILY;UNTIL=20240103T090000Z", start: monday,
+			want: []string{"01-01", "01-02"}},
+		{name: "daily interval", rule: "FREQ=DAILY;INTERVAL=3;COUNT=3", start: monday,
+			want: []string{"01-01", "01-04", "01-07"}},
+		{name: "daily by day", rule: "FREQ=DAILY;BYDAY=SA,SU;COUNT=3", start: monday,
+			want: []string{"01-06", "01-07", "01-13"}},
+		{name: "weekly on the start day", rule: "FREQ=WEEKLY;COUNT=3", start: wednesday,
+			want: []string{"01-03", "01-10", "01-17"}},
+		{name: "weekly by day", rule: "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4", start: monday,
+			want: []string{"01-02", "01-04", "01-09", "01-11"}},
+		{name: "weekly by day skips days before start", rule: "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=3", start: wednesday,
+			want: []string{"01-05", "01-08", "01-12"}},
+		{name: "biweekly", rule: "FREQ=WEEKLY;INTERVAL=2;COUNT=3", start: monday,
+			want: []string{"01-01", "01-15", "01-29"}},
+		{name: "monthly on day 31 skips shorter months", rule: "FREQ=MONTHLY;COUNT=4", start: lastOfJanuary,
+			want: []string{"01-31", "03-31", "05-31", "07-31"}},
+		{name: "monthly on day 31 until", rule: "FREQ=MONTHLY;UNTIL=20240630", start: lastOfJanuary,
+			want: []string{"01-31", "03-31", "05-31"}},
+		{name: "no matching day", rule: "FREQ=DAILY;BYDAY=MO;UNTIL=20240105", start: wednesday, wantErr: true},
+		{name: "until before start", rule: "FREQ=DAILY;UNTIL=20231231", start: monday, wantErr: true},
+		{name: "until beyond the cap", rule: "FREQ=DAILY;UNTIL=20250201", start: monday, wantErr: true},
+		{name: "weekly until beyond the cap", rule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR,SA,SU;UNTIL=20250201", start: monday,
+			wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := rule.Occurrences(tt.start)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Occurrences() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Occurrences() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want days %v", got, tt.want)
+			}
+			for i, occurrence := range got {
+				if day := occurrence.Format("01-02"); day != tt.want[i] {
+					t.Errorf("occurrence %d on %s, want %s", i, day, tt.want[i])
+				}
+				if occurrence.Hour() != tt.start.Hour() || occurrence.Location() != tt.start.Location() {
+					t.Errorf("occurrence %d at %s, want the time of day of start", i, occurrence)
+				}
+			}
+		})
+	}
+}
+
+func TestOccurrencesCap(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	rule, err := Parse("FREQ=DAILY;COUNT=366")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := rule.Occurrences(start)
+	if err != nil {
+		t.Fatalf("Occurrences() error = %v", err)
+	}
+	if len(got) != MaxOccurrences {
+		t.Fatalf("got %d occurrences, want %d", len(got), MaxOccurrences)
+	}
+	if last := got[len(got)-1]; !last.Equal(start.AddDate(0, 0, MaxOccurrences-1)) {
+		t.Errorf("last occurrence %s, want %s", last, start.AddDate(0, 0, MaxOccurrences-1))
+	}
+
+	// a year and a day of daily occurrences is one too many
+	rule, err = Parse("FREQ=DAILY;UNTIL=20250101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err = rule.Occurrences(start); err == nil {
+		t.Errorf("Occurrences() = %d occurrences, want error above %d", len(got), MaxOccurrences)
+	}
+}
+
+func sameDays(a []time.Weekday, b []time.Weekday) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"equiptrack/internal/equipment"
 	"equiptrack/internal/models"
 	"equiptrack/internal/utils"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,12 +22,18 @@ func NewEquipmentRepository(db *sql.DB) equipment.Repository {
 	return &equipmentRepo{db: db}
 }
 
-func (r *equipmentRepo) Create(ctx context.Context, equipment *models.Equipment) (*models.Equipment, error) {
+func (r *equipmentRepo) Create(ctx context.Context, equipment *models.Equipment, tagPrefix string) (*models.Equipment, error) {
 	e := &models.Equipment{}
 	if err := r.db.QueryRowContext(
-		ctx, qCreateEquipment, &equipment.Name, &equipment.ShortDescription, &equipment.FullDescription,
+		ctx, qCreateEquipment,
+		&equipment.Name, &equipment.ShortDescription, &equipment.FullDescription, &equipment.TeamID,
+		&equipment.RequiresApproval, &equipment.CustodianID, &equipment.Type,
+		&equipment.SerialNumber, tagPrefix, &equipment.AssetTag, &equipment.LocationID, &equipment.Quantity,
+		attributesArg(equipment.Attributes),
 	).Scan(
-		&e.Name, &e.ShortDescription, &e.FullDescription, &e.EquipmentID); err != nil {
+		&e.Name, &e.ShortDescription, &e.FullDescription, &e.EquipmentID, &e.TeamID,
+		&e.RequiresApproval, &e.CustodianID, &e.Type, &e.SerialNumber, &e.AssetTag, &e.LocationID,
+		&e.Status, &e.Quantity, &e.Attributes); err != nil {
 		return nil, errors.Wrap(err, "equipmentRepo.Create.StructScan")
 	}
 	return e, nil
@@ -33,7 +41,10 @@ func (r *equipmentRepo) Create(ctx context.Context, equipment *models.Equipment)
 
 func (r *equipmentRepo) Update(ctx context.Context, equipment *models.Equipment) error {
 	result, err := r.db.ExecContext(
-		ctx, qUpdateEquipment, &equipment.Name, &equipment.ShortDescription, &equipment.FullDescription, &equipment.EquipmentID,
+		ctx, qUpdateEquipment,
+		&equipment.Name, &equipment.ShortDescription, &equipment.FullDescription, &equipment.TeamID,
+		&equipment.RequiresApproval, &equipment.CustodianID, &equipment.Type, &equipment.EquipmentID,
+		&equipment.SerialNumber, &equipment.AssetTag, &equipment.Quantity, attributesArg(equipment.Attributes),
 	)
 	if err != nil {
 		return errors.Wrap(err, "equipmentRepo.Update.ExecContext")
@@ -48,19 +59,101 @@ func (r *equipmentRepo) Update(ctx context.Context, equipment *models.Equipment)
 	return nil
 }
 
-func (r *equipmentRepo) Delete(ctx context.Context, equipmentID uuid.UUID) error {
-	result, err := r.db.ExecContext(ctx, qDeleteEquipment, equipmentID)
+// Empty attributes are passed as NULL
+func attributesArg(attributes []byte) interface{} {
+	if len(attributes) == 0 {
+		return nil
+	}
+	return attributes
+}
+
+// Hides the equipment from the catalog. Unless forced, equipment with reservations which
+// haven't ended yet isn't archived and false is returned. Forcing cancels those reservations
+// and returns them, waitlist entries are cancelled either way
+func (r *equipmentRepo) Archive(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	force bool,
+) ([]models.UsersEquipment, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "equipmentRepo.Archive.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, qLockEquipment, equipmentID); err != nil {
+		return nil, false, errors.Wrap(err, "equipmentRepo.Archive.LockEquipment")
+	}
+
+	cancelled := make([]models.UsersEquipment, 0)
+	if !force {
+		var upcoming bool
+		if err := tx.QueryRowContext(ctx, qHasUpcomingReservations, equipmentID).Scan(&upcoming); err != nil {
+			return nil, false, errors.Wrap(err, "equipmentRepo.Archive.HasUpcomingReservations")
+		}
+		if upcoming {
+			return nil, false, nil
+		}
+	} else {
+		rows, err := tx.QueryContext(ctx, qCancelUpcomingReservations, equipmentID)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "equipmentRepo.Archive.CancelUpcomingReservations")
+		}
+		cancelled, err = scanReservations(rows)
+		rows.Close()
+		if err != nil {
+			return nil, false, errors.Wrap(err, "equipmentRepo.Archive.CancelUpcomingReservations.ScanRows")
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, qCancelWaitlist, equipmentID); err != nil {
+		return nil, false, errors.Wrap(err, "equipmentRepo.Archive.CancelWaitlist")
+	}
+	result, err := tx.ExecContext(ctx, qArchiveEquipment, equipmentID)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "equipmentRepo.Archive.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "equipmentRepo.Archive.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return nil, false, errors.Wrap(sql.ErrNoRows, "equipmentRepo.Archive.rowsAffected")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, errors.Wrap(err, "equipmentRepo.Archive.Commit")
+	}
+	return cancelled, true, nil
+}
+
+func (r *equipmentRepo) Restore(ctx context.Context, equipmentID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qRestoreEquipment, equipmentID)
 	if err != nil {
-		return errors.WithMessage(err, "equipmentRepo.Delete.ExecContext")
+		return errors.Wrap(err, "equipmentRepo.Restore.ExecContext")
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "equipmentRepo.Delete.RowsAffected")
+		return errors.Wrap(err, "equipmentRepo.Restore.RowsAffected")
 	}
 	if rowsAffected == 0 {
-		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.Delete.rowsAffected")
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.Restore.rowsAffected")
 	}
+	return nil
+}
 
+func (r *equipmentRepo) SetStatus(ctx context.Context, equipmentID uuid.UUID, status string) error {
+	result, err := r.db.ExecContext(ctx, qSetEquipmentStatus, status, equipmentID)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.SetStatus.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.SetStatus.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.SetStatus.rowsAffected")
+	}
 	return nil
 }
 
@@ -71,20 +164,64 @@ func (r *equipmentRepo) GetByID(ctx context.Context, equipmentID uuid.UUID) (*mo
 		&equipment.Name,
 		&equipment.ShortDescription,
 		&equipment.FullDescription,
+		&equipment.TeamID,
+		&equipment.RequiresApproval,
+		&equipment.CustodianID,
+		&equipment.Type,
+		&equipment.ArchivedAt,
+		&equipment.SerialNumber,
+		&equipment.AssetTag,
+		&equipment.LocationID,
+		&equipment.Status,
+		&equipment.Quantity,
+		&equipment.Attributes,
 	); err != nil {
 		return nil, errors.Wrap(err, "equipmentRepo.GetByID.QueryRowContext")
 	}
 	return equipment, nil
 }
 
-func (r *equipmentRepo) getTotalCount(ctx context.Context) (int, error) {
+// Finds equipment by its asset tag or serial number
+func (r *equipmentRepo) GetIDByCode(ctx context.Context, code string) (uuid.UUID, error) {
+	var equipmentID uuid.UUID
+	if err := r.db.QueryRowContext(ctx, qGetEquipmentIDByCode, code).Scan(&equipmentID); err != nil {
+		return uuid.Nil, errors.Wrap(err, "equipmentRepo.GetIDByCode.QueryRowContext")
+	}
+	return equipmentID, nil
+}
+
+func (r *equipmentRepo) IsTeamMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (bool, error) {
+	var member bool
+	if err := r.db.QueryRowContext(ctx, qIsTeamMember, teamID, userID).Scan(&member); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.IsTeamMember.QueryRowContext")
+	}
+	return member, nil
+}
+
+func (r *equipmentRepo) getTotalCount(ctx context.Context, filter *models.EquipmentFilter) (int, error) {
+	attributes, err := attributeFilters(filter)
+	if err != nil {
+		return 0, errors.Wrap(err, "equipmentRepo.getTotalCount.attributeFilters")
+	}
 	var totalCount int
-	if err := r.db.QueryRowContext(ctx, qGetTotal).Scan(&totalCount); err != nil {
+	if err := r.db.QueryRowContext(
+		ctx, qGetTotal, filter.ViewAll, filter.ViewerID, filter.Archived, filter.LocationID, attributes,
+	).Scan(&totalCount); err != nil {
 		return 0, errors.Wrap(err, "equipmentRepo.getTotalCount.QueryRowContext")
 	}
 	return totalCount, nil
 }
 
+// Attribute filters as the JSON array the listing queries expect
+func attributeFilters(filter *models.EquipmentFilter) (string, error) {
+	attributes := filter.Attributes
+	if attributes == nil {
+		attributes = make([]models.AttributeFilter, 0)
+	}
+	raw, err := json.Marshal(attributes)
+	return string(raw), err
+}
+
 func (r *equipmentRepo) getReservedByUserCount(ctx context.Context, userId uuid.UUID) (int, error) {
 	var totalCount int
 	if err := r.db.QueryRowContext(ctx, qGetTotalReservedByUser, userId).Scan(&totalCount); err != nil {
@@ -93,8 +230,12 @@ func (r *equipmentRepo) getReservedByUserCount(ctx context.Context, userId uuid.
 	return totalCount, nil
 }
 
-func (r *equipmentRepo) GetEquipments(ctx context.Context, pq *utils.PaginationQuery) (*models.EquipmentList, error) {
-	totalCount, err := r.getTotalCount(ctx)
+func (r *equipmentRepo) GetEquipments(
+	ctx context.Context,
+	pq *utils.PaginationQuery,
+	filter *models.EquipmentFilter,
+) (*models.EquipmentList, error) {
+	totalCount, err := r.getTotalCount(ctx, filter)
 	if err != nil {
 		return nil, errors.Wrap(err, "equipmentRepo.GetEquipments.totalCount")
 	}
@@ -110,11 +251,20 @@ func (r *equipmentRepo) GetEquipments(ctx context.Context, pq *utils.PaginationQ
 		}, nil
 	}
 
+	attributes, err := attributeFilters(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetEquipments.attributeFilters")
+	}
 	rows, err := r.db.QueryContext(
 		ctx,
 		qGetEquipments,
 		pq.GetOffset(),
 		pq.GetLimit(),
+		filter.ViewAll,
+		filter.ViewerID,
+		filter.Archived,
+		filter.LocationID,
+		attributes,
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "equipmentRepo.GetEquipments.QueryContext")
@@ -123,7 +273,8 @@ func (r *equipmentRepo) GetEquipments(ctx context.Context, pq *utils.PaginationQ
 	var equipments = make([]models.Equipment, 0, pq.GetSize())
 	for rows.Next() {
 		var r models.Equipment
-		err := rows.Scan(&r.EquipmentID, &r.Name, &r.ShortDescription, &r.Reserved)
+		err := rows.Scan(&r.EquipmentID, &r.Name, &r.ShortDescription, &r.Reserved, &r.TeamID, &r.Type, &r.ArchivedAt,
+			&r.SerialNumber, &r.AssetTag, &r.LocationID, &r.Status, &r.Quantity, &r.Attributes)
 		if err != nil {
 			return nil, errors.Wrap(err, "equipmentRepo.GetEquipments.QueryContext.ScanRows")
 		}
@@ -170,7 +321,7 @@ func (r *equipmentRepo) GetUserEquipments(ctx context.Context, pq *utils.Paginat
 	var equipments = make([]models.Equipment, 0, pq.GetSize())
 	for rows.Next() {
 		var r models.Equipment
-		err := rows.Scan(&r.EquipmentID, &r.Name, &r.ShortDescription, &r.Reserved)
+		err := rows.Scan(&r.EquipmentID, &r.Name, &r.ShortDescription, &r.Reserved, &r.TeamID, &r.Type)
 		if err != nil {
 			return nil, errors.Wrap(err, "equipmentRepo.GetUserEquipments.QueryContext.ScanRows")
 		}
@@ -202,7 +353,7 @@ func (r *equipmentRepo) GetReservationInfo(ctx context.Context, equipmentId uuid
 	var info = make([]models.ReservationInfo, 0)
 	for rows.Next() {
 		var r models.ReservationInfo
-		err := rows.Scan(&r.ReservationEnd, &r.ReservationStart)
+		err := rows.Scan(&r.ReservationStart, &r.ReservationEnd, &r.Status)
 		if err != nil {
 			return nil, errors.Wrap(err, "equipmentRepo.GetReservationInfo.QueryContext.ScanRows")
 		}
@@ -216,27 +367,1237 @@ func (r *equipmentRepo) GetReservationInfo(ctx context.Context, equipmentId uuid
 	}, nil
 }
 
-func (r *equipmentRepo) IsEquipmentReservedAt(ctx context.Context, equipmentId uuid.UUID, start time.Time, end time.Time) (bool, error) {
+// Reports whether the units don't fit into the window anymore
+func (r *equipmentRepo) IsEquipmentReservedAt(
+	ctx context.Context,
+	equipmentId uuid.UUID,
+	start time.Time,
+	end time.Time,
+	quantity int,
+) (bool, error) {
 	var busy bool
-	if err := r.db.QueryRowContext(ctx, qIsReserved, equipmentId, start, end).Scan(&busy); err == sql.ErrNoRows {
-		return false, nil
-	} else if err != nil {
+	if err := r.db.QueryRowContext(ctx, qIsReserved, equipmentId, start, end, quantity).Scan(&busy); err != nil {
 		return true, errors.Wrap(err, "equipmentRepo.IsEquipmentReservedAt.QueryRowContext")
 	}
-	return true, nil
+	return busy, nil
 }
 
+// The equipment stays locked from the availability check until the reservation is
+// stored, so concurrent requests can't both take the last free units
 func (r *equipmentRepo) ReserveEquipment(ctx context.Context, ue *models.UsersEquipment) (bool, error) {
-	var reserved, err = r.IsEquipmentReservedAt(ctx, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return false, err
+		return false, errors.Wrap(err, "equipmentRepo.ReserveEquipment.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, qLockEquipment, ue.EquipmentID); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.ReserveEquipment.LockEquipment")
 	}
-	if reserved {
+	var busy bool
+	if err := tx.QueryRowContext(
+		ctx, qIsReserved, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd, ue.Quantity,
+	).Scan(&busy); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.ReserveEquipment.IsReserved")
+	}
+	if busy {
 		return false, nil
 	}
-	_, err = r.db.ExecContext(ctx, qReserve, ue.UserID, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd)
+	if err := tx.QueryRowContext(
+		ctx, qReserve, ue.UserID, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd, ue.Status, ue.SeriesID, ue.Quantity,
+	).Scan(&ue.Id); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.ReserveEquipment.Reserve")
+	}
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.ReserveEquipment.Commit")
+	}
+	return true, nil
+}
+
+func (r *equipmentRepo) GetBookingUsage(
+	ctx context.Context,
+	userID uuid.UUID,
+	monthStart time.Time,
+	equipmentType string,
+) (*models.BookingUsage, error) {
+	usage := &models.BookingUsage{}
+	if err := r.db.QueryRowContext(
+		ctx, qGetBookingUsage, userID, monthStart, monthStart.AddDate(0, 1, 0), equipmentType,
+	).Scan(&usage.ActiveReservations, &usage.HoursInMonth); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetBookingUsage.QueryRowContext")
+	}
+	return usage, nil
+}
+
+func (r *equipmentRepo) GetReservation(ctx context.Context, reservationID int) (*models.UsersEquipment, error) {
+	ue := &models.UsersEquipment{}
+	if err := r.db.QueryRowContext(ctx, qGetReservation, reservationID).Scan(
+		&ue.Id,
+		&ue.UserID,
+		&ue.EquipmentID,
+		&ue.ReservationStart,
+		&ue.ReservationEnd,
+		&ue.Status,
+		&ue.RejectionReason,
+		&ue.SeriesID,
+		&ue.Quantity,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetReservation.QueryRowContext")
+	}
+	return ue, nil
+}
+
+func (r *equipmentRepo) GetPendingReservations(ctx context.Context, approver *models.User) ([]models.UsersEquipment, error) {
+	rows, err := r.db.QueryContext(ctx, qGetPendingReservations, approver.IsAdmin(), approver.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetPendingReservations.QueryContext")
+	}
+	defer rows.Close()
+
+	var reservations = make([]models.UsersEquipment, 0)
+	for rows.Next() {
+		var ue models.UsersEquipment
+		if err := rows.Scan(
+			&ue.Id,
+			&ue.UserID,
+			&ue.EquipmentID,
+			&ue.ReservationStart,
+			&ue.ReservationEnd,
+			&ue.Status,
+			&ue.RejectionReason,
+			&ue.SeriesID,
+			&ue.Quantity,
+		); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetPendingReservations.QueryContext.ScanRows")
+		}
+		reservations = append(reservations, ue)
+	}
+	return reservations, nil
+}
+
+// Moves reservation to the new status only if it's still in the expected one
+func (r *equipmentRepo) UpdateReservationStatus(ctx context.Context, reservationID int, from string, to string, reason string) error {
+	result, err := r.db.ExecContext(ctx, qUpdateReservationStatus, to, reason, reservationID, from)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateReservationStatus.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateReservationStatus.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.UpdateReservationStatus.rowsAffected")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) ShortenReservation(ctx context.Context, reservationID int, end time.Time) error {
+	result, err := r.db.ExecContext(ctx, qShortenReservation, end, reservationID)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.ShortenReservation.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.ShortenReservation.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.ShortenReservation.rowsAffected")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) error {
+	if err := r.db.QueryRowContext(
+		ctx, qJoinWaitlist, entry.UserID, entry.EquipmentID, entry.WindowStart, entry.WindowEnd,
+	).Scan(&entry.Id, &entry.Status, &entry.CreatedAt); err != nil {
+		return errors.Wrap(err, "equipmentRepo.JoinWaitlist.QueryRowContext")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) GetWaitlistEntry(ctx context.Context, entryID int) (*models.WaitlistEntry, error) {
+	entry := &models.WaitlistEntry{}
+	if err := r.db.QueryRowContext(ctx, qGetWaitlistEntry, entryID).Scan(
+		&entry.Id,
+		&entry.UserID,
+		&entry.EquipmentID,
+		&entry.WindowStart,
+		&entry.WindowEnd,
+		&entry.Status,
+		&entry.ReservationID,
+		&entry.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetWaitlistEntry.QueryRowContext")
+	}
+	return entry, nil
+}
+
+func (r *equipmentRepo) GetUserWaitlist(ctx context.Context, userID uuid.UUID) ([]models.WaitlistEntry, error) {
+	return r.getWaitlist(ctx, qGetUserWaitlist, userID)
+}
+
+func (r *equipmentRepo) GetEquipmentWaitlist(ctx context.Context, equipmentID uuid.UUID) ([]models.WaitlistEntry, error) {
+	return r.getWaitlist(ctx, qGetEquipmentWaitlist, equipmentID)
+}
+
+func (r *equipmentRepo) getWaitlist(ctx context.Context, query string, id uuid.UUID) ([]models.WaitlistEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.getWaitlist.QueryContext")
+	}
+	defer rows.Close()
+
+	var entries = make([]models.WaitlistEntry, 0)
+	for rows.Next() {
+		var entry models.WaitlistEntry
+		if err := rows.Scan(
+			&entry.Id,
+			&entry.UserID,
+			&entry.EquipmentID,
+			&entry.WindowStart,
+			&entry.WindowEnd,
+			&entry.Status,
+			&entry.ReservationID,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.getWaitlist.QueryContext.ScanRows")
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (r *equipmentRepo) GetWaitingEntries(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	start time.Time,
+	end time.Time,
+) ([]models.WaitingEntry, error) {
+	rows, err := r.db.QueryContext(ctx, qGetWaitingEntries, equipmentID, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetWaitingEntries.QueryContext")
+	}
+	defer rows.Close()
+
+	var entries = make([]models.WaitingEntry, 0)
+	for rows.Next() {
+		var entry models.WaitingEntry
+		if err := rows.Scan(
+			&entry.Id,
+			&entry.UserID,
+			&entry.EquipmentID,
+			&entry.WindowStart,
+			&entry.WindowEnd,
+			&entry.Status,
+			&entry.ReservationID,
+			&entry.CreatedAt,
+			&entry.Role,
+		); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetWaitingEntries.QueryContext.ScanRows")
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Moves waitlist entry to the new status only if it's still in the expected one
+func (r *equipmentRepo) UpdateWaitlistStatus(ctx context.Context, entryID int, from string, to string, reservationID *int) error {
+	result, err := r.db.ExecContext(ctx, qUpdateWaitlistStatus, to, reservationID, entryID, from)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateWaitlistStatus.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateWaitlistStatus.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.UpdateWaitlistStatus.rowsAffected")
+	}
+	return nil
+}
+
+// Creates the series with all its occurrences or nothing. When some occurrences
+// collide with existing reservations they are returned and nothing is stored
+func (r *equipmentRepo) CreateSeries(
+	ctx context.Context,
+	series *models.ReservationSeries,
+	occurrences []models.UsersEquipment,
+) ([]models.UsersEquipment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.CreateSeries.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, qLockEquipment, series.EquipmentID); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.CreateSeries.LockEquipment")
+	}
+	if err := tx.QueryRowContext(
+		ctx, qCreateSeries, series.UserID, series.EquipmentID, series.Rule, series.ReservationStart, series.ReservationEnd,
+	).Scan(&series.SeriesID, &series.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.CreateSeries.CreateSeries")
+	}
+
+	conflicts := make([]models.UsersEquipment, 0)
+	for i := range occurrences {
+		ue := &occurrences[i]
+		var busy bool
+		if err := tx.QueryRowContext(
+			ctx, qIsReserved, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd, ue.Quantity,
+		).Scan(&busy); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.CreateSeries.IsReserved")
+		}
+		if busy {
+			conflicts = append(conflicts, *ue)
+			continue
+		}
+
+		ue.SeriesID = &series.SeriesID
+		if err := tx.QueryRowContext(
+			ctx, qReserve, ue.UserID, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd, ue.Status, ue.SeriesID,
+			ue.Quantity,
+		).Scan(&ue.Id); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.CreateSeries.Reserve")
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.CreateSeries.Commit")
+	}
+	series.Occurrences = occurrences
+	return conflicts, nil
+}
+
+func (r *equipmentRepo) GetSeries(ctx context.Context, seriesID uuid.UUID) (*models.ReservationSeries, error) {
+	series := &models.ReservationSeries{}
+	if err := r.db.QueryRowContext(ctx, qGetSeries, seriesID).Scan(
+		&series.SeriesID,
+		&series.UserID,
+		&series.EquipmentID,
+		&series.Rule,
+		&series.ReservationStart,
+		&series.ReservationEnd,
+		&series.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetSeries.QueryRowContext")
+	}
+
+	rows, err := r.db.QueryContext(ctx, qGetSeriesReservations, seriesID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetSeries.QueryContext")
+	}
+	defer rows.Close()
+
+	if series.Occurrences, err = scanReservations(rows); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetSeries.ScanRows")
+	}
+	if len(series.Occurrences) > 0 {
+		series.Quantity = series.Occurrences[0].Quantity
+	}
+	return series, nil
+}
+
+// Cancels occurrences which haven't ended yet and returns them
+func (r *equipmentRepo) CancelSeries(ctx context.Context, seriesID uuid.UUID) ([]models.UsersEquipment, error) {
+	rows, err := r.db.QueryContext(ctx, qCancelSeries, seriesID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.CancelSeries.QueryContext")
+	}
+	defer rows.Close()
+
+	cancelled, err := scanReservations(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.CancelSeries.ScanRows")
+	}
+	return cancelled, nil
+}
+
+// Moves all given reservations of one equipment to their new windows or none of them.
+// Reservations colliding with other ones are returned and nothing is changed
+func (r *equipmentRepo) RescheduleReservations(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	reservations []models.UsersEquipment,
+) ([]models.UsersEquipment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.RescheduleReservations.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, qLockEquipment, equipmentID); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.RescheduleReservations.LockEquipment")
+	}
+
+	conflicts := make([]models.UsersEquipment, 0)
+	for _, ue := range reservations {
+		var busy bool
+		if err := tx.QueryRowContext(
+			ctx, qIsReservedExcept, equipmentID, ue.ReservationStart, ue.ReservationEnd, ue.Id,
+		).Scan(&busy); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.RescheduleReservations.IsReserved")
+		}
+		if busy {
+			conflicts = append(conflicts, ue)
+			continue
+		}
+
+		result, err := tx.ExecContext(ctx, qRescheduleReservation, ue.ReservationStart, ue.ReservationEnd, ue.Status, ue.Id)
+		if err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.RescheduleReservations.ExecContext")
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.RescheduleReservations.RowsAffected")
+		}
+		if rowsAffected == 0 {
+			return nil, errors.Wrap(sql.ErrNoRows, "equipmentRepo.RescheduleReservations.rowsAffected")
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.RescheduleReservations.Commit")
+	}
+	return conflicts, nil
+}
+
+func scanReservations(rows *sql.Rows) ([]models.UsersEquipment, error) {
+	var reservations = make([]models.UsersEquipment, 0)
+	for rows.Next() {
+		var ue models.UsersEquipment
+		if err := rows.Scan(
+			&ue.Id,
+			&ue.UserID,
+			&ue.EquipmentID,
+			&ue.ReservationStart,
+			&ue.ReservationEnd,
+			&ue.Status,
+			&ue.RejectionReason,
+			&ue.SeriesID,
+			&ue.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, ue)
+	}
+	return reservations, rows.Err()
+}
+
+// Records the handover unless the equipment's possession changed meanwhile: check out needs
+// the equipment returned, check in needs it checked out on the same reservation. Units of
+// pooled equipment are checked out on several reservations at once
+func (r *equipmentRepo) CreateHandover(ctx context.Context, h *models.Handover) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return false, errors.Wrap(err, "equipmentRepo.ReserveEquipment.ExecContext")
+		return false, errors.Wrap(err, "equipmentRepo.CreateHandover.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, qLockEquipment, h.EquipmentID); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateHandover.LockEquipment")
+	}
+	var checkedOut int
+	if err := tx.QueryRowContext(ctx, qGetCheckedOutReservation, h.EquipmentID).Scan(&checkedOut); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateHandover.CheckedOut")
+	}
+	var quantity int
+	if err := tx.QueryRowContext(ctx, qGetEquipmentQuantity, h.EquipmentID).Scan(&quantity); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateHandover.Quantity")
+	}
+	var reservationOut bool
+	if err := tx.QueryRowContext(ctx, qIsReservationCheckedOut, h.ReservationID).Scan(&reservationOut); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateHandover.ReservationCheckedOut")
+	}
+	if (h.Kind == models.HandoverCheckOut && (reservationOut || (quantity == 1 && checkedOut != 0))) ||
+		(h.Kind == models.HandoverCheckIn && !reservationOut) {
+		return false, nil
+	}
+
+	if err := tx.QueryRowContext(
+		ctx, qCreateHandover, h.ReservationID, h.EquipmentID, h.UserID, h.Kind, h.HandledBy, h.Condition, h.Notes,
+	).Scan(&h.Id, &h.CreatedAt); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateHandover.QueryRowContext")
+	}
+	if h.Kind == models.HandoverCheckIn && h.LocationID != nil {
+		if _, err := moveEquipment(ctx, tx, &models.LocationChange{
+			EquipmentID:   h.EquipmentID,
+			ToLocationID:  h.LocationID,
+			ReservationID: &h.ReservationID,
+			MovedBy:       h.HandledBy,
+		}); err != nil {
+			return false, errors.Wrap(err, "equipmentRepo.CreateHandover.MoveEquipment")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateHandover.Commit")
 	}
 	return true, nil
 }
+
+// Reservation the equipment is checked out on, 0 when it's returned
+func (r *equipmentRepo) GetCheckedOutReservation(ctx context.Context, equipmentID uuid.UUID) (int, error) {
+	var reservationID int
+	if err := r.db.QueryRowContext(ctx, qGetCheckedOutReservation, equipmentID).Scan(&reservationID); err != nil {
+		return 0, errors.Wrap(err, "equipmentRepo.GetCheckedOutReservation.QueryRowContext")
+	}
+	return reservationID, nil
+}
+
+func (r *equipmentRepo) GetReservationHandovers(ctx context.Context, reservationID int) ([]models.Handover, error) {
+	rows, err := r.db.QueryContext(ctx, qGetReservationHandovers, reservationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetReservationHandovers.QueryContext")
+	}
+	defer rows.Close()
+
+	handovers, err := scanHandovers(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetReservationHandovers.ScanRows")
+	}
+	return handovers, nil
+}
+
+func (r *equipmentRepo) GetEquipmentHandovers(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	pq *utils.PaginationQuery,
+) ([]models.Handover, error) {
+	rows, err := r.db.QueryContext(ctx, qGetEquipmentHandovers, equipmentID, pq.GetOffset(), pq.GetLimit())
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetEquipmentHandovers.QueryContext")
+	}
+	defer rows.Close()
+
+	handovers, err := scanHandovers(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetEquipmentHandovers.ScanRows")
+	}
+	return handovers, nil
+}
+
+func scanHandovers(rows *sql.Rows) ([]models.Handover, error) {
+	var handovers = make([]models.Handover, 0)
+	for rows.Next() {
+		var h models.Handover
+		if err := rows.Scan(
+			&h.Id,
+			&h.ReservationID,
+			&h.EquipmentID,
+			&h.UserID,
+			&h.Kind,
+			&h.HandledBy,
+			&h.Condition,
+			&h.Notes,
+			&h.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		handovers = append(handovers, h)
+	}
+	return handovers, rows.Err()
+}
+
+// Moves ended reservations whose equipment wasn't returned to overdue and returns them
+func (r *equipmentRepo) MarkOverdue(ctx context.Context) ([]models.DueReservation, error) {
+	return r.claimDue(ctx, "equipmentRepo.MarkOverdue", qMarkOverdue)
+}
+
+// Returns confirmed reservations starting before the given time, each only once
+func (r *equipmentRepo) ClaimStartReminders(ctx context.Context, before time.Time) ([]models.DueReservation, error) {
+	return r.claimDue(ctx, "equipmentRepo.ClaimStartReminders", qClaimStartReminders, before)
+}
+
+// Returns checked out reservations ending before the given time, each only once
+func (r *equipmentRepo) ClaimEndReminders(ctx context.Context, before time.Time) ([]models.DueReservation, error) {
+	return r.claimDue(ctx, "equipmentRepo.ClaimEndReminders", qClaimEndReminders, before)
+}
+
+func (r *equipmentRepo) claimDue(ctx context.Context, op string, query string, args ...interface{}) ([]models.DueReservation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, op+".QueryContext")
+	}
+	defer rows.Close()
+
+	var due = make([]models.DueReservation, 0)
+	for rows.Next() {
+		var d models.DueReservation
+		if err := rows.Scan(
+			&d.Id,
+			&d.UserID,
+			&d.EquipmentID,
+			&d.ReservationStart,
+			&d.ReservationEnd,
+			&d.Status,
+			&d.RejectionReason,
+			&d.SeriesID,
+			&d.Quantity,
+			&d.EquipmentName,
+		); err != nil {
+			return nil, errors.Wrap(err, op+".ScanRows")
+		}
+		due = append(due, d)
+	}
+	return due, nil
+}
+
+func (r *equipmentRepo) CreateAttachment(ctx context.Context, a *models.Attachment) error {
+	if err := r.db.QueryRowContext(
+		ctx, qCreateAttachment,
+		a.AttachmentID, a.EquipmentID, a.Kind, a.FileName, a.ContentType, a.Size, a.StorageKey, a.ThumbnailKey, a.UploadedBy,
+	).Scan(&a.CreatedAt); err != nil {
+		return errors.Wrap(err, "equipmentRepo.CreateAttachment.QueryRowContext")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) GetAttachment(ctx context.Context, equipmentID uuid.UUID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	a := &models.Attachment{}
+	if err := r.db.QueryRowContext(ctx, qGetAttachment, attachmentID, equipmentID).Scan(
+		&a.AttachmentID,
+		&a.EquipmentID,
+		&a.Kind,
+		&a.FileName,
+		&a.ContentType,
+		&a.Size,
+		&a.StorageKey,
+		&a.ThumbnailKey,
+		&a.UploadedBy,
+		&a.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetAttachment.QueryRowContext")
+	}
+	return a, nil
+}
+
+func (r *equipmentRepo) GetAttachments(ctx context.Context, equipmentID uuid.UUID) ([]models.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, qGetAttachments, equipmentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetAttachments.QueryContext")
+	}
+	defer rows.Close()
+
+	var attachments = make([]models.Attachment, 0)
+	for rows.Next() {
+		var a models.Attachment
+		if err := rows.Scan(
+			&a.AttachmentID,
+			&a.EquipmentID,
+			&a.Kind,
+			&a.FileName,
+			&a.ContentType,
+			&a.Size,
+			&a.StorageKey,
+			&a.ThumbnailKey,
+			&a.UploadedBy,
+			&a.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetAttachments.ScanRows")
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *equipmentRepo) DeleteAttachment(ctx context.Context, equipmentID uuid.UUID, attachmentID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qDeleteAttachment, attachmentID, equipmentID)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.DeleteAttachment.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.DeleteAttachment.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.DeleteAttachment.rowsAffected")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) LocationExists(ctx context.Context, locationID uuid.UUID) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, qLocationExists, locationID).Scan(&exists); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.LocationExists.QueryRowContext")
+	}
+	return exists, nil
+}
+
+// Moves the equipment and records the change, false is returned when it's there already
+func (r *equipmentRepo) MoveEquipment(ctx context.Context, change *models.LocationChange) (bool, error) {
+	moved, err := moveEquipment(ctx, r.db, change)
+	if err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.MoveEquipment")
+	}
+	return moved, nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func moveEquipment(ctx context.Context, q queryRower, change *models.LocationChange) (bool, error) {
+	if err := q.QueryRowContext(
+		ctx, qMoveEquipment, change.EquipmentID, change.ToLocationID, change.ReservationID, change.MovedBy,
+	).Scan(&change.Id, &change.FromLocationID, &change.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *equipmentRepo) GetLocationHistory(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	pq *utils.PaginationQuery,
+) ([]models.LocationChange, error) {
+	rows, err := r.db.QueryContext(ctx, qGetLocationHistory, equipmentID, pq.GetOffset(), pq.GetLimit())
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetLocationHistory.QueryContext")
+	}
+	defer rows.Close()
+
+	var changes = make([]models.LocationChange, 0)
+	for rows.Next() {
+		var lc models.LocationChange
+		if err := rows.Scan(
+			&lc.Id,
+			&lc.EquipmentID,
+			&lc.FromLocationID,
+			&lc.ToLocationID,
+			&lc.ReservationID,
+			&lc.MovedBy,
+			&lc.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetLocationHistory.ScanRows")
+		}
+		changes = append(changes, lc)
+	}
+	return changes, rows.Err()
+}
+
+// Schedules the maintenance and returns the active reservations it collides with. The
+// equipment is locked so reservations made meanwhile either see the window or are returned
+func (r *equipmentRepo) ScheduleMaintenance(ctx context.Context, m *models.Maintenance) ([]models.UsersEquipment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ScheduleMaintenance.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, qLockEquipment, m.EquipmentID); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ScheduleMaintenance.LockEquipment")
+	}
+	if err := tx.QueryRowContext(
+		ctx, qCreateMaintenance, m.EquipmentID, m.WindowStart, m.WindowEnd, m.Reason, m.CreatedBy,
+	).Scan(&m.MaintenanceID, &m.Status, &m.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ScheduleMaintenance.QueryRowContext")
+	}
+
+	rows, err := tx.QueryContext(ctx, qGetCollidingReservations, m.EquipmentID, m.WindowStart, m.WindowEnd)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ScheduleMaintenance.CollidingReservations")
+	}
+	collisions, err := scanReservations(rows)
+	rows.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ScheduleMaintenance.CollidingReservations.ScanRows")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ScheduleMaintenance.Commit")
+	}
+	return collisions, nil
+}
+
+func (r *equipmentRepo) GetMaintenance(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	maintenanceID int,
+) (*models.Maintenance, error) {
+	m := &models.Maintenance{}
+	if err := r.db.QueryRowContext(ctx, qGetMaintenance, maintenanceID, equipmentID).Scan(
+		&m.MaintenanceID, &m.EquipmentID, &m.WindowStart, &m.WindowEnd, &m.Reason, &m.Status, &m.CreatedBy, &m.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetMaintenance.QueryRowContext")
+	}
+	return m, nil
+}
+
+// Scheduled maintenance which hasn't ended yet
+func (r *equipmentRepo) GetEquipmentMaintenance(ctx context.Context, equipmentID uuid.UUID) ([]models.Maintenance, error) {
+	rows, err := r.db.QueryContext(ctx, qGetEquipmentMaintenance, equipmentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetEquipmentMaintenance.QueryContext")
+	}
+	defer rows.Close()
+
+	var maintenance = make([]models.Maintenance, 0)
+	for rows.Next() {
+		var m models.Maintenance
+		if err := rows.Scan(
+			&m.MaintenanceID, &m.EquipmentID, &m.WindowStart, &m.WindowEnd, &m.Reason, &m.Status, &m.CreatedBy, &m.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetEquipmentMaintenance.ScanRows")
+		}
+		maintenance = append(maintenance, m)
+	}
+	return maintenance, rows.Err()
+}
+
+func (r *equipmentRepo) CancelMaintenance(ctx context.Context, equipmentID uuid.UUID, maintenanceID int) error {
+	result, err := r.db.ExecContext(ctx, qCancelMaintenance, maintenanceID, equipmentID)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.CancelMaintenance.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.CancelMaintenance.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.CancelMaintenance.rowsAffected")
+	}
+	return nil
+}
+
+// Registers a unit of pooled equipment, false is returned when the pool has as many units as its quantity
+func (r *equipmentRepo) CreateUnit(ctx context.Context, unit *models.EquipmentUnit, tagPrefix string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateUnit.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, qLockEquipment, unit.EquipmentID); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateUnit.LockEquipment")
+	}
+	var quantity, units int
+	if err := tx.QueryRowContext(ctx, qGetEquipmentQuantity, unit.EquipmentID).Scan(&quantity); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateUnit.Quantity")
+	}
+	if err := tx.QueryRowContext(ctx, qCountUnits, unit.EquipmentID).Scan(&units); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateUnit.CountUnits")
+	}
+	if units >= quantity {
+		return false, nil
+	}
+
+	if err := tx.QueryRowContext(
+		ctx, qCreateUnit, unit.EquipmentID, unit.SerialNumber, tagPrefix, unit.AssetTag,
+	).Scan(&unit.UnitID, &unit.EquipmentID, &unit.SerialNumber, &unit.AssetTag, &unit.Status, &unit.CreatedAt); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateUnit.QueryRowContext")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, "equipmentRepo.CreateUnit.Commit")
+	}
+	return true, nil
+}
+
+func (r *equipmentRepo) UpdateUnit(ctx context.Context, unit *models.EquipmentUnit) error {
+	result, err := r.db.ExecContext(
+		ctx, qUpdateUnit, unit.SerialNumber, unit.AssetTag, unit.Status, unit.UnitID, unit.EquipmentID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateUnit.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateUnit.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.UpdateUnit.rowsAffected")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) DeleteUnit(ctx context.Context, equipmentID uuid.UUID, unitID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qDeleteUnit, unitID, equipmentID)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.DeleteUnit.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.DeleteUnit.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.DeleteUnit.rowsAffected")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) GetUnit(ctx context.Context, equipmentID uuid.UUID, unitID uuid.UUID) (*models.EquipmentUnit, error) {
+	u := &models.EquipmentUnit{}
+	if err := r.db.QueryRowContext(ctx, qGetUnit, unitID, equipmentID).Scan(
+		&u.UnitID, &u.EquipmentID, &u.SerialNumber, &u.AssetTag, &u.Status, &u.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetUnit.QueryRowContext")
+	}
+	return u, nil
+}
+
+func (r *equipmentRepo) GetUnits(ctx context.Context, equipmentID uuid.UUID) ([]models.EquipmentUnit, error) {
+	rows, err := r.db.QueryContext(ctx, qGetUnits, equipmentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetUnits.QueryContext")
+	}
+	defer rows.Close()
+
+	var units = make([]models.EquipmentUnit, 0)
+	for rows.Next() {
+		var u models.EquipmentUnit
+		if err := rows.Scan(&u.UnitID, &u.EquipmentID, &u.SerialNumber, &u.AssetTag, &u.Status, &u.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetUnits.ScanRows")
+		}
+		units = append(units, u)
+	}
+	return units, rows.Err()
+}
+
+func (r *equipmentRepo) CountUnits(ctx context.Context, equipmentID uuid.UUID) (int, error) {
+	var units int
+	if err := r.db.QueryRowContext(ctx, qCountUnits, equipmentID).Scan(&units); err != nil {
+		return 0, errors.Wrap(err, "equipmentRepo.CountUnits.QueryRowContext")
+	}
+	return units, nil
+}
+
+func (r *equipmentRepo) GetBookedPeak(ctx context.Context, equipmentID uuid.UUID) (int, error) {
+	var peak int
+	if err := r.db.QueryRowContext(ctx, qGetBookedPeak, equipmentID).Scan(&peak); err != nil {
+		return 0, errors.Wrap(err, "equipmentRepo.GetBookedPeak.QueryRowContext")
+	}
+	return peak, nil
+}
+
+func (r *equipmentRepo) CreateKit(ctx context.Context, kit *models.Kit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.CreateKit.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, qCreateKit, kit.Name, kit.Description).Scan(&kit.KitID, &kit.CreatedAt); err != nil {
+		return errors.Wrap(err, "equipmentRepo.CreateKit.QueryRowContext")
+	}
+	if err := createKitItems(ctx, tx, kit); err != nil {
+		return errors.Wrap(err, "equipmentRepo.CreateKit.CreateKitItems")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "equipmentRepo.CreateKit.Commit")
+	}
+	return nil
+}
+
+// Replaces name, description and the items of the kit
+func (r *equipmentRepo) UpdateKit(ctx context.Context, kit *models.Kit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateKit.BeginTx")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, qUpdateKit, kit.Name, kit.Description, kit.KitID)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateKit.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateKit.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.UpdateKit.rowsAffected")
+	}
+	if _, err := tx.ExecContext(ctx, qDeleteKitItems, kit.KitID); err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateKit.DeleteKitItems")
+	}
+	if err := createKitItems(ctx, tx, kit); err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateKit.CreateKitItems")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "equipmentRepo.UpdateKit.Commit")
+	}
+	return nil
+}
+
+func createKitItems(ctx context.Context, tx *sql.Tx, kit *models.Kit) error {
+	for _, item := range kit.Items {
+		if _, err := tx.ExecContext(ctx, qCreateKitItem, kit.KitID, item.EquipmentID, item.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *equipmentRepo) DeleteKit(ctx context.Context, kitID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qDeleteKit, kitID)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.DeleteKit.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.DeleteKit.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.DeleteKit.rowsAffected")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) GetKit(ctx context.Context, kitID uuid.UUID) (*models.Kit, error) {
+	kit := &models.Kit{}
+	if err := r.db.QueryRowContext(ctx, qGetKit, kitID).Scan(
+		&kit.KitID, &kit.Name, &kit.Description, &kit.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetKit.QueryRowContext")
+	}
+
+	items, err := r.getKitItems(ctx, &kitID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetKit.GetKitItems")
+	}
+	kit.Items = items[kitID]
+	return kit, nil
+}
+
+func (r *equipmentRepo) GetKits(ctx context.Context) ([]models.Kit, error) {
+	rows, err := r.db.QueryContext(ctx, qGetKits)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetKits.QueryContext")
+	}
+	defer rows.Close()
+
+	var kits = make([]models.Kit, 0)
+	for rows.Next() {
+		var kit models.Kit
+		if err := rows.Scan(&kit.KitID, &kit.Name, &kit.Description, &kit.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetKits.ScanRows")
+		}
+		kits = append(kits, kit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetKits.Rows")
+	}
+
+	items, err := r.getKitItems(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetKits.GetKitItems")
+	}
+	for i := range kits {
+		kits[i].Items = items[kits[i].KitID]
+	}
+	return kits, nil
+}
+
+// Items grouped by kit, of every kit when kitID is nil
+func (r *equipmentRepo) getKitItems(ctx context.Context, kitID *uuid.UUID) (map[uuid.UUID][]models.KitItem, error) {
+	rows, err := r.db.QueryContext(ctx, qGetKitItems, kitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[uuid.UUID][]models.KitItem)
+	for rows.Next() {
+		var id uuid.UUID
+		var item models.KitItem
+		if err := rows.Scan(&id, &item.EquipmentID, &item.Quantity, &item.EquipmentName); err != nil {
+			return nil, err
+		}
+		items[id] = append(items[id], item)
+	}
+	return items, rows.Err()
+}
+
+// Books every component of the kit or none of them. Components are locked in a fixed
+// order so concurrent kit reservations sharing equipment don't deadlock. Components
+// which don't fit are returned and nothing is reserved
+func (r *equipmentRepo) ReserveKit(
+	ctx context.Context,
+	kr *models.KitReservation,
+	reservations []models.UsersEquipment,
+) ([]models.UsersEquipment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ReserveKit.BeginTx")
+	}
+	defer tx.Rollback()
+
+	locks := make([]uuid.UUID, 0, len(reservations))
+	for _, ue := range reservations {
+		locks = append(locks, ue.EquipmentID)
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].String() < locks[j].String() })
+	for _, equipmentID := range locks {
+		if _, err := tx.ExecContext(ctx, qLockEquipment, equipmentID); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.ReserveKit.LockEquipment")
+		}
+	}
+
+	conflicts := make([]models.UsersEquipment, 0)
+	for _, ue := range reservations {
+		var busy bool
+		if err := tx.QueryRowContext(
+			ctx, qIsReserved, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd, ue.Quantity,
+		).Scan(&busy); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.ReserveKit.IsReserved")
+		}
+		if busy {
+			conflicts = append(conflicts, ue)
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	if err := tx.QueryRowContext(
+		ctx, qCreateKitReservation, kr.KitID, kr.UserID, kr.ReservationStart, kr.ReservationEnd,
+	).Scan(&kr.KitReservationID, &kr.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ReserveKit.CreateKitReservation")
+	}
+	for i := range reservations {
+		ue := &reservations[i]
+		if err := tx.QueryRowContext(
+			ctx, qReserve, ue.UserID, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd, ue.Status, ue.SeriesID,
+			ue.Quantity,
+		).Scan(&ue.Id); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.ReserveKit.Reserve")
+		}
+		if _, err := tx.ExecContext(ctx, qCreateKitReservationItem, kr.KitReservationID, ue.Id); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.ReserveKit.CreateKitReservationItem")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.ReserveKit.Commit")
+	}
+	kr.Reservations = reservations
+	return conflicts, nil
+}
+
+func (r *equipmentRepo) GetKitReservation(ctx context.Context, kitReservationID uuid.UUID) (*models.KitReservation, error) {
+	kr := &models.KitReservation{}
+	if err := r.db.QueryRowContext(ctx, qGetKitReservation, kitReservationID).Scan(
+		&kr.KitReservationID, &kr.KitID, &kr.UserID, &kr.ReservationStart, &kr.ReservationEnd, &kr.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetKitReservation.QueryRowContext")
+	}
+
+	rows, err := r.db.QueryContext(ctx, qGetKitReservationItems, kitReservationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetKitReservation.QueryContext")
+	}
+	defer rows.Close()
+
+	if kr.Reservations, err = scanReservations(rows); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetKitReservation.ScanRows")
+	}
+	return kr, nil
+}
+
+// Cancels component reservations which haven't ended yet and returns them
+func (r *equipmentRepo) CancelKitReservation(
+	ctx context.Context,
+	kitReservationID uuid.UUID,
+) ([]models.UsersEquipment, error) {
+	rows, err := r.db.QueryContext(ctx, qCancelKitReservation, kitReservationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.CancelKitReservation.QueryContext")
+	}
+	defer rows.Close()
+
+	cancelled, err := scanReservations(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.CancelKitReservation.ScanRows")
+	}
+	return cancelled, nil
+}
+
+// Creates the type or replaces its schema
+func (r *equipmentRepo) SetEquipmentType(ctx context.Context, equipmentType *models.EquipmentType) error {
+	if err := r.db.QueryRowContext(
+		ctx, qSetEquipmentType, equipmentType.Type, []byte(equipmentType.Schema),
+	).Scan(&equipmentType.UpdatedAt); err != nil {
+		return errors.Wrap(err, "equipmentRepo.SetEquipmentType.QueryRowContext")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) DeleteEquipmentType(ctx context.Context, equipmentType string) error {
+	result, err := r.db.ExecContext(ctx, qDeleteEquipmentType, equipmentType)
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.DeleteEquipmentType.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "equipmentRepo.DeleteEquipmentType.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "equipmentRepo.DeleteEquipmentType.rowsAffected")
+	}
+	return nil
+}
+
+func (r *equipmentRepo) GetEquipmentType(ctx context.Context, equipmentType string) (*models.EquipmentType, error) {
+	t := &models.EquipmentType{}
+	if err := r.db.QueryRowContext(ctx, qGetEquipmentType, equipmentType).Scan(
+		&t.Type, &t.Schema, &t.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetEquipmentType.QueryRowContext")
+	}
+	return t, nil
+}
+
+func (r *equipmentRepo) GetEquipmentTypes(ctx context.Context) ([]models.EquipmentType, error) {
+	rows, err := r.db.QueryContext(ctx, qGetEquipmentTypes)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetEquipmentTypes.QueryContext")
+	}
+	defer rows.Close()
+
+	var types = make([]models.EquipmentType, 0)
+	for rows.Next() {
+		var t models.EquipmentType
+		if err := rows.Scan(&t.Type, &t.Schema, &t.UpdatedAt); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetEquipmentTypes.ScanRows")
+		}
+		types = append(types, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetEquipmentTypes.Rows")
+	}
+	return types, nil
+}
+
+// Attributes of the catalog equipment of the type by equipment
+func (r *equipmentRepo) GetTypeAttributes(ctx context.Context, equipmentType string) (map[uuid.UUID]json.RawMessage, error) {
+	rows, err := r.db.QueryContext(ctx, qGetTypeAttributes, equipmentType)
+	if err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetTypeAttributes.QueryContext")
+	}
+	defer rows.Close()
+
+	attributes := make(map[uuid.UUID]json.RawMessage)
+	for rows.Next() {
+		var equipmentID uuid.UUID
+		var attrs json.RawMessage
+		if err := rows.Scan(&equipmentID, &attrs); err != nil {
+			return nil, errors.Wrap(err, "equipmentRepo.GetTypeAttributes.ScanRows")
+		}
+		attributes[equipmentID] = attrs
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "equipmentRepo.GetTypeAttributes.Rows")
+	}
+	return attributes, nil
+}
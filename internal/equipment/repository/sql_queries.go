@@ -1,29 +1,122 @@
 package repository
 
+// whether attribute filter f matches the equipment row
+const attributeMatches = `CASE f.op
+			WHEN '=' THEN equipment.attributes ->> f.name = f.value
+			WHEN '!=' THEN equipment.attributes ->> f.name IS DISTINCT FROM f.value
+			ELSE CASE WHEN jsonb_typeof(equipment.attributes -> f.name) = 'number' THEN CASE f.op
+				WHEN '>' THEN (equipment.attributes ->> f.name)::numeric > f.value::numeric
+				WHEN '>=' THEN (equipment.attributes ->> f.name)::numeric >= f.value::numeric
+				WHEN '<' THEN (equipment.attributes ->> f.name)::numeric < f.value::numeric
+				WHEN '<=' THEN (equipment.attributes ->> f.name)::numeric <= f.value::numeric
+			END END
+		END`
+
 const (
-	qCreateEquipment = `INSERT INTO equipment (name, short_description, full_description) VALUES ($1, $2, $3) RETURNING *`
-	qUpdateEquipment = `UPDATE equipment SET name=$1, short_description=$2, full_description=$3 WHERE equipment_id=$4`
-	qDeleteEquipment = `DELETE FROM equipment WHERE equipment_id = $1`
-	qGetEquipment    = `SELECT equipment_id, name, short_description, full_description FROM equipment WHERE equipment_id = $1`
+	// asset tags not given are numbered from asset_tag_seq, $9 is the prefix
+	qCreateEquipment = `INSERT INTO equipment (name, short_description, full_description, team_id, requires_approval, custodian_id, type,
+		serial_number, asset_tag, location_id, status, quantity, attributes)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''),
+		COALESCE(NULLIF($10, ''), $9 || LPAD(nextval('asset_tag_seq')::text, 6, '0')), $11, 'active', COALESCE(NULLIF($12, 0), 1),
+		COALESCE($13::jsonb, '{}'))
+	RETURNING name, short_description, full_description, equipment_id, team_id, requires_approval, custodian_id, type,
+		COALESCE(serial_number, ''), asset_tag, location_id, status, quantity, attributes`
+	// an empty asset tag, a zero quantity and NULL attributes keep the current ones, the location is
+	// changed by qMoveEquipment
+	qUpdateEquipment = `UPDATE equipment
+	SET name=$1, short_description=$2, full_description=$3, team_id=$4, requires_approval=$5, custodian_id=$6, type=$7,
+		serial_number=NULLIF($9, ''), asset_tag=COALESCE(NULLIF($10, ''), asset_tag), quantity=COALESCE(NULLIF($11, 0), quantity),
+		attributes=COALESCE($12::jsonb, attributes)
+	WHERE equipment_id=$8`
+	qGetEquipment = `SELECT equipment_id, name, short_description, full_description, team_id, requires_approval, custodian_id, type,
+		archived_at, COALESCE(serial_number, ''), asset_tag, location_id, status, quantity, attributes
+	FROM equipment
+	WHERE equipment_id = $1`
+	qSetEquipmentStatus = `UPDATE equipment SET status = $1 WHERE equipment_id = $2`
+	// codes scanned or typed by people, tags are matched case insensitively. Codes of
+	// pooled units resolve to their pool
+	qGetEquipmentIDByCode = `SELECT equipment_id
+	FROM equipment
+	WHERE lower(asset_tag) = lower($1) OR serial_number = $1
+	UNION ALL
+	SELECT equipment_id
+	FROM equipment_units
+	WHERE lower(asset_tag) = lower($1) OR serial_number = $1
+	LIMIT 1`
+
+	// archived equipment keeps its rows so reservation history stays intact
+	qArchiveEquipment = `UPDATE equipment SET archived_at = CURRENT_TIMESTAMP
+	WHERE equipment_id = $1 AND archived_at IS NULL`
+	qRestoreEquipment = `UPDATE equipment SET archived_at = NULL
+	WHERE equipment_id = $1 AND archived_at IS NOT NULL`
+	// overdue reservations ended but the equipment is still out on them
+	qHasUpcomingReservations = `SELECT EXISTS (
+		SELECT 1 FROM usersEquipment
+		WHERE equipment_id = $1
+		AND (status IN ('pending', 'confirmed') AND reservation_end > CURRENT_TIMESTAMP OR status = 'overdue')
+	)`
+	qCancelUpcomingReservations = `UPDATE usersEquipment
+	SET status = 'cancelled'
+	WHERE equipment_id = $1 AND status IN ('pending', 'confirmed') AND reservation_end > CURRENT_TIMESTAMP
+	RETURNING id, user_id, equipment_id, reservation_start, reservation_end, status,
+		COALESCE(rejection_reason, ''), series_id, quantity`
+	qCancelWaitlist = `UPDATE waitlist SET status = 'cancelled' WHERE equipment_id = $1 AND status = 'waiting'`
+
+	qIsTeamMember = `SELECT EXISTS (SELECT 1 FROM team_members WHERE team_id = $1 AND user_id = $2)`
 
-	qGetTotal               = `SELECT COUNT(equipment_id) FROM equipment`
+	// location filter covers everything nested in the location. Attribute filters are a JSON array
+	// of {name, op, value}, equipment is listed when none of them fails. Equality compares the
+	// attribute as text, ordering only matches numeric attributes
+	qGetTotal = `SELECT COUNT(equipment_id)
+	FROM equipment
+	WHERE (archived_at IS NOT NULL) = $3
+	AND ($1 OR team_id IS NULL OR team_id IN (SELECT team_id FROM team_members WHERE user_id = $2))
+	AND ($4::uuid IS NULL OR location_id IN (
+		WITH RECURSIVE subtree AS (
+			SELECT location_id FROM locations WHERE location_id = $4
+			UNION ALL
+			SELECT l.location_id FROM locations l INNER JOIN subtree s ON l.parent_id = s.location_id
+		)
+		SELECT location_id FROM subtree
+	))
+	AND NOT EXISTS (
+		SELECT 1 FROM jsonb_to_recordset($5::jsonb) AS f(name text, op text, value text)
+		WHERE NOT COALESCE(` + attributeMatches + `, false)
+	)`
 	qGetTotalReservedByUser = `SELECT COUNT(equipment_id) 
 								FROM (
 									SELECT DISTINCT equipment_id
 									FROM usersEquipment
-									WHERE user_id = $1 AND CURRENT_TIMESTAMP < reservation_end
+									WHERE user_id = $1
+									AND (CURRENT_TIMESTAMP < reservation_end AND status IN ('pending', 'confirmed')
+										OR status = 'overdue')
 								)`
 
-	qGetEquipments = `WITH nearest_reservations AS (
-		SELECT DISTINCT  equipment_id, MIN(reservation_start) AS reservation_start, MIN(reservation_end) AS reservation_end
-		FROM usersEquipment 
-		WHERE CURRENT_TIMESTAMP < reservation_end
-		GROUP BY equipment_id
-	)
-	SELECT equipment_id, name, short_description,
-		CASE WHEN reservation_start IS NULL THEN false ELSE CURRENT_TIMESTAMP BETWEEN reservation_start AND reservation_end END AS reserved
+	// equipment is reserved while it's checked out and not returned yet
+	qGetEquipments = `SELECT equipment_id, name, short_description,
+		EXISTS (
+			SELECT 1 FROM equipment_handovers h
+			WHERE h.equipment_id = equipment.equipment_id AND h.kind = 'check_out'
+			AND NOT EXISTS (
+				SELECT 1 FROM equipment_handovers r WHERE r.reservation_id = h.reservation_id AND r.kind = 'check_in'
+			)
+		) AS reserved,
+		team_id, type, archived_at, COALESCE(serial_number, ''), asset_tag, location_id, status, quantity, attributes
 	FROM equipment
-	LEFT JOIN nearest_reservations using(equipment_id)
+	WHERE (archived_at IS NOT NULL) = $5
+	AND ($3 OR team_id IS NULL OR team_id IN (SELECT team_id FROM team_members WHERE user_id = $4))
+	AND ($6::uuid IS NULL OR location_id IN (
+		WITH RECURSIVE subtree AS (
+			SELECT location_id FROM locations WHERE location_id = $6
+			UNION ALL
+			SELECT l.location_id FROM locations l INNER JOIN subtree s ON l.parent_id = s.location_id
+		)
+		SELECT location_id FROM subtree
+	))
+	AND NOT EXISTS (
+		SELECT 1 FROM jsonb_to_recordset($7::jsonb) AS f(name text, op text, value text)
+		WHERE NOT COALESCE(` + attributeMatches + `, false)
+	)
 	ORDER BY reserved
 	OFFSET $1 
 	LIMIT $2`
@@ -31,10 +124,22 @@ const (
 	// qGetEquipments = `SELECT equipment_id, name, short_description
 	// 				 FROM equipment
 	// 				 ORDER BY COALESCE(NULLIF($1, ''), name) OFFSET $2 LIMIT $3`
-	qGetUserEquipments = `SELECT DISTINCT equipment_id, name, short_description, true AS reserved
-	FROM equipment 
-	INNER JOIN usersEquipment using(equipment_id)
-	WHERE user_id = $3 AND CURRENT_TIMESTAMP < reservation_end
+	qGetUserEquipments = `SELECT equipment_id, name, short_description,
+		EXISTS (
+			SELECT 1 FROM equipment_handovers h
+			WHERE h.equipment_id = equipment.equipment_id AND h.user_id = $3 AND h.kind = 'check_out'
+			AND NOT EXISTS (
+				SELECT 1 FROM equipment_handovers r WHERE r.reservation_id = h.reservation_id AND r.kind = 'check_in'
+			)
+		) AS reserved,
+		team_id, type
+	FROM equipment
+	WHERE equipment_id IN (
+		SELECT equipment_id
+		FROM usersEquipment
+		WHERE user_id = $3
+		AND (CURRENT_TIMESTAMP < reservation_end AND status IN ('pending', 'confirmed') OR status = 'overdue')
+	)
 	OFFSET $1 LIMIT $2`
 
 	// qGetReservationInfo = `SELECT reservation_start, reservation_end
@@ -42,18 +147,361 @@ const (
 	// 					WHERE equipment_id = $1
 	// 					AND CURRENT_TIMESTAMP BETWEEN reservation_start AND reservation_end`
 
-	qGetReservationInfo = `SELECT reservation_start, reservation_end
+	// maintenance windows are listed as taken slots too
+	qGetReservationInfo = `SELECT reservation_start, reservation_end, status
 	FROM usersEquipment
+	WHERE equipment_id = $1 AND status IN ('pending', 'confirmed', 'overdue')
+	UNION ALL
+	SELECT window_start, window_end, 'maintenance'
+	FROM equipment_maintenance
+	WHERE equipment_id = $1 AND status = 'scheduled'
+	ORDER BY 1`
+
+	// pending reservations hold their units until they are decided, scheduled maintenance blocks
+	// every unit. Units in use peak at the window start or at a reservation starting inside it,
+	// $4 units don't fit when that peak leaves less of the quantity free
+	qIsReserved = `SELECT (
+					SELECT COALESCE(MAX(used), 0)
+					FROM (
+						SELECT (
+							SELECT COALESCE(SUM(o.quantity), 0)
+							FROM usersEquipment o
+							WHERE o.equipment_id = $1
+							AND o.status IN ('pending', 'confirmed')
+							AND o.reservation_start <= p.t AND o.reservation_end > p.t
+						) AS used
+						FROM (
+							SELECT $2::timestamptz AS t
+							UNION
+							SELECT reservation_start
+							FROM usersEquipment
+							WHERE equipment_id = $1
+							AND status IN ('pending', 'confirmed')
+							AND reservation_start > $2 AND reservation_start < $3
+						) p
+					) peak
+				) + $4 > (SELECT quantity FROM equipment WHERE equipment_id = $1)
+				OR EXISTS (
+					SELECT 1
+					FROM equipment_maintenance
+					WHERE equipment_id = $1
+					AND status = 'scheduled'
+					AND window_start < $3 AND window_end > $2
+				)`
+
+	qReserve = `INSERT INTO usersEquipment (user_id, equipment_id, reservation_start, reservation_end, status, series_id, quantity)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id`
+
+	// serializes concurrent reservations and reservation changes of the same equipment
+	qLockEquipment = `SELECT equipment_id FROM equipment WHERE equipment_id = $1 FOR UPDATE`
+	// same as qIsReserved for the units of reservation $4 ignoring the reservation itself
+	qIsReservedExcept = `SELECT (
+					SELECT COALESCE(MAX(used), 0)
+					FROM (
+						SELECT (
+							SELECT COALESCE(SUM(o.quantity), 0)
+							FROM usersEquipment o
+							WHERE o.equipment_id = $1
+							AND o.status IN ('pending', 'confirmed')
+							AND o.reservation_start <= p.t AND o.reservation_end > p.t
+							AND o.id <> $4
+						) AS used
+						FROM (
+							SELECT $2::timestamptz AS t
+							UNION
+							SELECT reservation_start
+							FROM usersEquipment
+							WHERE equipment_id = $1
+							AND status IN ('pending', 'confirmed')
+							AND reservation_start > $2 AND reservation_start < $3
+							AND id <> $4
+						) p
+					) peak
+				) + (SELECT quantity FROM usersEquipment WHERE id = $4)
+					> (SELECT quantity FROM equipment WHERE equipment_id = $1)
+				OR EXISTS (
+					SELECT 1
+					FROM equipment_maintenance
+					WHERE equipment_id = $1
+					AND status = 'scheduled'
+					AND window_start < $3 AND window_end > $2
+				)`
+	qRescheduleReservation = `UPDATE usersEquipment
+	SET reservation_start = $1, reservation_end = $2, status = $3
+	WHERE id = $4 AND status IN ('pending', 'confirmed')`
+
+	qCreateSeries = `INSERT INTO reservation_series (user_id, equipment_id, rule, reservation_start, reservation_end)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING series_id, created_at`
+	qGetSeries = `SELECT series_id, user_id, equipment_id, rule, reservation_start, reservation_end, created_at
+	FROM reservation_series
+	WHERE series_id = $1`
+	qGetSeriesReservations = `SELECT id, user_id, equipment_id, reservation_start, reservation_end, status,
+		COALESCE(rejection_reason, ''), series_id, quantity
+	FROM usersEquipment
+	WHERE series_id = $1
+	ORDER BY reservation_start`
+	qCancelSeries = `UPDATE usersEquipment
+	SET status = 'cancelled'
+	WHERE series_id = $1 AND status IN ('pending', 'confirmed') AND reservation_end > CURRENT_TIMESTAMP
+	RETURNING id, user_id, equipment_id, reservation_start, reservation_end, status,
+		COALESCE(rejection_reason, ''), series_id, quantity`
+
+	// active reservations count and booked hours of reservations starting in [$2, $3),
+	// optionally limited to one equipment type. Overdue reservations stay active until
+	// the equipment is returned
+	qGetBookingUsage = `SELECT
+		COUNT(*) FILTER (WHERE ue.reservation_end > CURRENT_TIMESTAMP OR ue.status = 'overdue'),
+		COALESCE(SUM(EXTRACT(EPOCH FROM ue.reservation_end - ue.reservation_start))
+			FILTER (WHERE ue.reservation_start >= $2 AND ue.reservation_start < $3), 0) / 3600
+	FROM usersEquipment ue
+	INNER JOIN equipment e using(equipment_id)
+	WHERE ue.user_id = $1 AND ue.status IN ('pending', 'confirmed', 'overdue') AND ($4 = '' OR e.type = $4)`
+
+	qGetReservation = `SELECT id, user_id, equipment_id, reservation_start, reservation_end, status,
+		COALESCE(rejection_reason, ''), series_id, quantity
+	FROM usersEquipment
+	WHERE id = $1`
+	qGetPendingReservations = `SELECT ue.id, ue.user_id, ue.equipment_id, ue.reservation_start, ue.reservation_end,
+		ue.status, COALESCE(ue.rejection_reason, ''), ue.series_id, ue.quantity
+	FROM usersEquipment ue
+	INNER JOIN equipment e using(equipment_id)
+	WHERE ue.status = 'pending' AND ($1 OR e.custodian_id = $2)
+	ORDER BY ue.reservation_start`
+	qUpdateReservationStatus = `UPDATE usersEquipment
+	SET status = $1, rejection_reason = NULLIF($2, '')
+	WHERE id = $3 AND status = $4`
+	qShortenReservation = `UPDATE usersEquipment
+	SET reservation_end = $1
+	WHERE id = $2 AND status IN ('pending', 'confirmed') AND reservation_start < $1 AND reservation_end > $1`
+
+	qJoinWaitlist = `INSERT INTO waitlist (user_id, equipment_id, window_start, window_end, status)
+	VALUES ($1, $2, $3, $4, 'waiting')
+	RETURNING id, status, created_at`
+	qGetWaitlistEntry = `SELECT id, user_id, equipment_id, window_start, window_end, status, reservation_id, created_at
+	FROM waitlist
+	WHERE id = $1`
+	qGetUserWaitlist = `SELECT id, user_id, equipment_id, window_start, window_end, status, reservation_id, created_at
+	FROM waitlist
+	WHERE user_id = $1 AND window_end > CURRENT_TIMESTAMP
+	ORDER BY window_start`
+	qGetEquipmentWaitlist = `SELECT id, user_id, equipment_id, window_start, window_end, status, reservation_id, created_at
+	FROM waitlist
+	WHERE equipment_id = $1 AND status = 'waiting' AND window_end > CURRENT_TIMESTAMP
+	ORDER BY created_at, id`
+	// waiting entries overlapping the freed window in the order they joined, with the waiter's role
+	// needed to evaluate booking policies
+	qGetWaitingEntries = `SELECT w.id, w.user_id, w.equipment_id, w.window_start, w.window_end, w.status,
+		w.reservation_id, w.created_at, u.role
+	FROM waitlist w
+	INNER JOIN users u using(user_id)
+	WHERE w.equipment_id = $1 AND w.status = 'waiting'
+	AND w.window_start < $3 AND w.window_end > $2 AND w.window_end > CURRENT_TIMESTAMP
+	ORDER BY w.created_at, w.id`
+	qUpdateWaitlistStatus = `UPDATE waitlist
+	SET status = $1, reservation_id = $2
+	WHERE id = $3 AND status = $4`
+
+	qCreateHandover = `INSERT INTO equipment_handovers (reservation_id, equipment_id, user_id, kind, handled_by, condition, notes)
+	VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''))
+	RETURNING id, created_at`
+	qGetReservationHandovers = `SELECT id, reservation_id, equipment_id, user_id, kind, handled_by,
+		COALESCE(condition, ''), COALESCE(notes, ''), created_at
+	FROM equipment_handovers
+	WHERE reservation_id = $1
+	ORDER BY created_at`
+	qGetEquipmentHandovers = `SELECT id, reservation_id, equipment_id, user_id, kind, handled_by,
+		COALESCE(condition, ''), COALESCE(notes, ''), created_at
+	FROM equipment_handovers
+	WHERE equipment_id = $1
+	ORDER BY created_at DESC
+	OFFSET $2
+	LIMIT $3`
+	// reservation whose check out isn't followed by a check in yet
+	qGetCheckedOutReservation = `SELECT COALESCE((
+		SELECT h.reservation_id
+		FROM equipment_handovers h
+		WHERE h.equipment_id = $1 AND h.kind = 'check_out'
+		AND NOT EXISTS (
+			SELECT 1 FROM equipment_handovers r WHERE r.reservation_id = h.reservation_id AND r.kind = 'check_in'
+		)
+		LIMIT 1
+	), 0)`
+	qIsReservationCheckedOut = `SELECT EXISTS (
+		SELECT 1 FROM equipment_handovers h
+		WHERE h.reservation_id = $1 AND h.kind = 'check_out'
+		AND NOT EXISTS (
+			SELECT 1 FROM equipment_handovers r WHERE r.reservation_id = h.reservation_id AND r.kind = 'check_in'
+		)
+	)`
+	qGetEquipmentQuantity = `SELECT quantity FROM equipment WHERE equipment_id = $1`
+
+	// jobs claim the rows they handle by updating them, so with a crash in between
+	// a reminder is rather lost than sent twice
+	qMarkOverdue = `UPDATE usersEquipment ue
+	SET status = 'overdue'
+	FROM equipment e
+	WHERE e.equipment_id = ue.equipment_id
+	AND ue.status = 'confirmed' AND ue.reservation_end < CURRENT_TIMESTAMP
+	AND EXISTS (SELECT 1 FROM equipment_handovers h WHERE h.reservation_id = ue.id AND h.kind = 'check_out')
+	AND NOT EXISTS (SELECT 1 FROM equipment_handovers h WHERE h.reservation_id = ue.id AND h.kind = 'check_in')
+	RETURNING ue.id, ue.user_id, ue.equipment_id, ue.reservation_start, ue.reservation_end, ue.status,
+		COALESCE(ue.rejection_reason, ''), ue.series_id, ue.quantity, e.name`
+	qClaimStartReminders = `UPDATE usersEquipment ue
+	SET start_reminder_sent_at = CURRENT_TIMESTAMP
+	FROM equipment e
+	WHERE e.equipment_id = ue.equipment_id
+	AND ue.status = 'confirmed' AND ue.start_reminder_sent_at IS NULL
+	AND ue.reservation_start > CURRENT_TIMESTAMP AND ue.reservation_start <= $1
+	RETURNING ue.id, ue.user_id, ue.equipment_id, ue.reservation_start, ue.reservation_end, ue.status,
+		COALESCE(ue.rejection_reason, ''), ue.series_id, ue.quantity, e.name`
+	qClaimEndReminders = `UPDATE usersEquipment ue
+	SET end_reminder_sent_at = CURRENT_TIMESTAMP
+	FROM equipment e
+	WHERE e.equipment_id = ue.equipment_id
+	AND ue.status = 'confirmed' AND ue.end_reminder_sent_at IS NULL
+	AND ue.reservation_end > CURRENT_TIMESTAMP AND ue.reservation_end <= $1
+	AND EXISTS (SELECT 1 FROM equipment_handovers h WHERE h.reservation_id = ue.id AND h.kind = 'check_out')
+	AND NOT EXISTS (SELECT 1 FROM equipment_handovers h WHERE h.reservation_id = ue.id AND h.kind = 'check_in')
+	RETURNING ue.id, ue.user_id, ue.equipment_id, ue.reservation_start, ue.reservation_end, ue.status,
+		COALESCE(ue.rejection_reason, ''), ue.series_id, ue.quantity, e.name`
+
+	qCreateAttachment = `INSERT INTO equipment_attachments
+		(attachment_id, equipment_id, kind, file_name, content_type, size, storage_key, thumbnail_key, uploaded_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
+	RETURNING created_at`
+	qGetAttachment = `SELECT attachment_id, equipment_id, kind, file_name, content_type, size, storage_key,
+		COALESCE(thumbnail_key, ''), uploaded_by, created_at
+	FROM equipment_attachments
+	WHERE attachment_id = $1 AND equipment_id = $2`
+	qGetAttachments = `SELECT attachment_id, equipment_id, kind, file_name, content_type, size, storage_key,
+		COALESCE(thumbnail_key, ''), uploaded_by, created_at
+	FROM equipment_attachments
+	WHERE equipment_id = $1
+	ORDER BY created_at`
+	qDeleteAttachment = `DELETE FROM equipment_attachments WHERE attachment_id = $1 AND equipment_id = $2`
+
+	qLocationExists = `SELECT EXISTS (SELECT 1 FROM locations WHERE location_id = $1)`
+	// moves the equipment unless it's there already, the history row keeps where it came from
+	qMoveEquipment = `WITH previous AS (
+		SELECT location_id FROM equipment WHERE equipment_id = $1 FOR UPDATE
+	), moved AS (
+		UPDATE equipment SET location_id = $2
+		WHERE equipment_id = $1 AND location_id IS DISTINCT FROM $2::uuid
+		RETURNING equipment_id
+	)
+	INSERT INTO equipment_location_history (equipment_id, from_location_id, to_location_id, reservation_id, moved_by)
+	SELECT moved.equipment_id, previous.location_id, $2, $3, $4
+	FROM moved, previous
+	RETURNING id, from_location_id, created_at`
+	qGetLocationHistory = `SELECT id, equipment_id, from_location_id, to_location_id, reservation_id, moved_by, created_at
+	FROM equipment_location_history
 	WHERE equipment_id = $1
+	ORDER BY created_at DESC
+	OFFSET $2
+	LIMIT $3`
+
+	qCreateMaintenance = `INSERT INTO equipment_maintenance (equipment_id, window_start, window_end, reason, status, created_by)
+	VALUES ($1, $2, $3, NULLIF($4, ''), 'scheduled', $5)
+	RETURNING maintenance_id, status, created_at`
+	// active reservations overlapping the maintenance window
+	qGetCollidingReservations = `SELECT id, user_id, equipment_id, reservation_start, reservation_end, status,
+		COALESCE(rejection_reason, ''), series_id, quantity
+	FROM usersEquipment
+	WHERE equipment_id = $1 AND status IN ('pending', 'confirmed')
+	AND reservation_start < $3 AND reservation_end > $2
 	ORDER BY reservation_start`
+	qGetMaintenance = `SELECT maintenance_id, equipment_id, window_start, window_end, COALESCE(reason, ''), status,
+		created_by, created_at
+	FROM equipment_maintenance
+	WHERE maintenance_id = $1 AND equipment_id = $2`
+	qGetEquipmentMaintenance = `SELECT maintenance_id, equipment_id, window_start, window_end, COALESCE(reason, ''), status,
+		created_by, created_at
+	FROM equipment_maintenance
+	WHERE equipment_id = $1 AND status = 'scheduled' AND window_end > CURRENT_TIMESTAMP
+	ORDER BY window_start`
+	qCancelMaintenance = `UPDATE equipment_maintenance
+	SET status = 'cancelled'
+	WHERE maintenance_id = $1 AND equipment_id = $2 AND status = 'scheduled'`
 
-	qIsReserved = `SELECT true AS reserved
-					FROM usersEquipment
-					WHERE equipment_id = $1
-					AND ($2 BETWEEN reservation_start AND reservation_end
-					OR $3 BETWEEN reservation_start AND reservation_end)
-					LIMIT 1`
+	// units are numbered from the same sequence as equipment, a pool holds at most its quantity of units
+	qCountUnits = `SELECT COUNT(unit_id) FROM equipment_units WHERE equipment_id = $1`
+	// most units booked at once from now on, usage peaks now or when a reservation starts
+	qGetBookedPeak = `SELECT COALESCE(MAX(used), 0)
+	FROM (
+		SELECT (
+			SELECT COALESCE(SUM(o.quantity), 0)
+			FROM usersEquipment o
+			WHERE o.equipment_id = $1
+			AND o.status IN ('pending', 'confirmed')
+			AND o.reservation_start <= p.t AND o.reservation_end > p.t
+		) AS used
+		FROM (
+			SELECT CURRENT_TIMESTAMP AS t
+			UNION
+			SELECT reservation_start
+			FROM usersEquipment
+			WHERE equipment_id = $1
+			AND status IN ('pending', 'confirmed')
+			AND reservation_start > CURRENT_TIMESTAMP
+		) p
+	) peak`
+	qCreateUnit = `INSERT INTO equipment_units (equipment_id, serial_number, asset_tag, status)
+	VALUES ($1, NULLIF($2, ''), COALESCE(NULLIF($4, ''), $3 || LPAD(nextval('asset_tag_seq')::text, 6, '0')), 'active')
+	RETURNING unit_id, equipment_id, COALESCE(serial_number, ''), asset_tag, status, created_at`
+	qUpdateUnit = `UPDATE equipment_units
+	SET serial_number = NULLIF($1, ''), asset_tag = COALESCE(NULLIF($2, ''), asset_tag), status = COALESCE(NULLIF($3, ''), status)
+	WHERE unit_id = $4 AND equipment_id = $5`
+	qDeleteUnit = `DELETE FROM equipment_units WHERE unit_id = $1 AND equipment_id = $2`
+	qGetUnit    = `SELECT unit_id, equipment_id, COALESCE(serial_number, ''), asset_tag, status, created_at
+	FROM equipment_units
+	WHERE unit_id = $1 AND equipment_id = $2`
+	qGetUnits = `SELECT unit_id, equipment_id, COALESCE(serial_number, ''), asset_tag, status, created_at
+	FROM equipment_units
+	WHERE equipment_id = $1
+	ORDER BY asset_tag`
+
+	qCreateKit = `INSERT INTO kits (name, description) VALUES ($1, $2) RETURNING kit_id, created_at`
+	qUpdateKit = `UPDATE kits SET name = $1, description = $2 WHERE kit_id = $3`
+	qDeleteKit = `DELETE FROM kits WHERE kit_id = $1`
+	qGetKit    = `SELECT kit_id, name, description, created_at FROM kits WHERE kit_id = $1`
+	qGetKits   = `SELECT kit_id, name, description, created_at FROM kits ORDER BY name`
+
+	qCreateKitItem  = `INSERT INTO kit_items (kit_id, equipment_id, quantity) VALUES ($1, $2, $3)`
+	qDeleteKitItems = `DELETE FROM kit_items WHERE kit_id = $1`
+	// items of one kit, or of every kit when $1 is NULL
+	qGetKitItems = `SELECT ki.kit_id, ki.equipment_id, ki.quantity, e.name
+	FROM kit_items ki
+	INNER JOIN equipment e using(equipment_id)
+	WHERE $1::uuid IS NULL OR ki.kit_id = $1
+	ORDER BY e.name`
+
+	qCreateKitReservation = `INSERT INTO kit_reservations (kit_id, user_id, reservation_start, reservation_end)
+	VALUES ($1, $2, $3, $4)
+	RETURNING kit_reservation_id, created_at`
+	qCreateKitReservationItem = `INSERT INTO kit_reservation_items (kit_reservation_id, reservation_id) VALUES ($1, $2)`
+	qGetKitReservation        = `SELECT kit_reservation_id, kit_id, user_id, reservation_start, reservation_end, created_at
+	FROM kit_reservations
+	WHERE kit_reservation_id = $1`
+	qGetKitReservationItems = `SELECT id, user_id, equipment_id, reservation_start, reservation_end, status,
+		COALESCE(rejection_reason, ''), series_id, quantity
+	FROM usersEquipment
+	WHERE id IN (SELECT reservation_id FROM kit_reservation_items WHERE kit_reservation_id = $1)
+	ORDER BY id`
+	qCancelKitReservation = `UPDATE usersEquipment
+	SET status = 'cancelled'
+	WHERE id IN (SELECT reservation_id FROM kit_reservation_items WHERE kit_reservation_id = $1)
+	AND status IN ('pending', 'confirmed') AND reservation_end > CURRENT_TIMESTAMP
+	RETURNING id, user_id, equipment_id, reservation_start, reservation_end, status,
+		COALESCE(rejection_reason, ''), series_id, quantity`
 
-	qReserve = `INSERT INTO usersEquipment (user_id, equipment_id, reservation_start, reservation_end)
-				VALUES ($1, $2, $3, $4)`
+	qSetEquipmentType = `INSERT INTO equipment_types (type, schema) VALUES ($1, $2::jsonb)
+	ON CONFLICT (type) DO UPDATE SET schema = EXCLUDED.schema, updated_at = CURRENT_TIMESTAMP
+	RETURNING updated_at`
+	qDeleteEquipmentType = `DELETE FROM equipment_types WHERE type = $1`
+	qGetEquipmentType    = `SELECT type, schema, updated_at FROM equipment_types WHERE type = $1`
+	qGetEquipmentTypes   = `SELECT type, schema, updated_at FROM equipment_types ORDER BY type`
+	// equipment of the type whose attributes would break a new schema, checked in Go
+	qGetTypeAttributes = `SELECT equipment_id, attributes FROM equipment WHERE type = $1 AND archived_at IS NULL`
 )
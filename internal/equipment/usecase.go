@@ -2,19 +2,82 @@ package equipment
 
 import (
 	"context"
+	"equiptrack/config"
 	"equiptrack/internal/models"
 	"equiptrack/internal/utils"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type UseCase interface {
 	Create(ctx context.Context, equipment *models.Equipment) (*models.Equipment, error)
-	Update(ctx context.Context, equipment *models.Equipment) error
-	Delete(ctx context.Context, equipmentID uuid.UUID) error
+	Update(ctx context.Context, update *models.EquipmentUpdate) error
+	Delete(ctx context.Context, equipmentID uuid.UUID, force bool) error
+	Restore(ctx context.Context, equipmentID uuid.UUID) (*models.Equipment, error)
 	GetByID(ctx context.Context, equipmentID uuid.UUID) (*models.Equipment, error)
-	GetEquipments(ctx context.Context, pq *utils.PaginationQuery) (*models.EquipmentList, error)
+	Lookup(ctx context.Context, code string) (*models.Equipment, error)
+	UploadAttachment(ctx context.Context, equipmentID uuid.UUID, fileName string, file io.ReadSeeker, size int64) (*models.Attachment, error)
+	GetAttachmentContent(
+		ctx context.Context,
+		equipmentID uuid.UUID,
+		attachmentID uuid.UUID,
+		thumbnail bool,
+	) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, equipmentID uuid.UUID, attachmentID uuid.UUID) error
+	GetQRCode(ctx context.Context, equipmentID uuid.UUID, format string, size int) ([]byte, error)
+	GetLabelSheet(ctx context.Context, request *models.LabelSheetRequest) ([]byte, error)
+	GetLabelTemplates() []config.LabelTemplate
+	GetEquipments(ctx context.Context, pq *utils.PaginationQuery, filter *models.EquipmentFilter) (*models.EquipmentList, error)
+	GetArchivedEquipments(ctx context.Context, pq *utils.PaginationQuery) (*models.EquipmentList, error)
 	GetUserEquipments(ctx context.Context, pq *utils.PaginationQuery, userId uuid.UUID) (*models.EquipmentList, error)
 	GetReservationInfo(ctx context.Context, equipmentId uuid.UUID) (*models.ReservationInfoResponse, error)
 	ReserveEquipment(ctx context.Context, reservation *models.UsersEquipment) (bool, error)
+	GetPendingReservations(ctx context.Context) ([]models.UsersEquipment, error)
+	ApproveReservation(ctx context.Context, reservationID int) error
+	RejectReservation(ctx context.Context, reservationID int, reason string) error
+	CancelReservation(ctx context.Context, reservationID int) error
+	ShortenReservation(ctx context.Context, reservationID int, end time.Time) error
+	RescheduleReservation(ctx context.Context, reservationID int, start time.Time, end time.Time) ([]models.UsersEquipment, error)
+	CreateSeries(ctx context.Context, series *models.ReservationSeries) (*models.ReservationSeries, []models.UsersEquipment, error)
+	GetSeries(ctx context.Context, seriesID uuid.UUID) (*models.ReservationSeries, error)
+	UpdateSeries(ctx context.Context, seriesID uuid.UUID, start time.Time, end time.Time) ([]models.UsersEquipment, error)
+	CancelSeries(ctx context.Context, seriesID uuid.UUID) error
+	CheckOut(ctx context.Context, reservationID int, handover *models.Handover) (*models.Handover, error)
+	CheckIn(ctx context.Context, reservationID int, handover *models.Handover) (*models.Handover, error)
+	GetReservationHandovers(ctx context.Context, reservationID int) ([]models.Handover, error)
+	GetEquipmentHandovers(ctx context.Context, equipmentID uuid.UUID, pq *utils.PaginationQuery) ([]models.Handover, error)
+	GetLocationHistory(ctx context.Context, equipmentID uuid.UUID, pq *utils.PaginationQuery) ([]models.LocationChange, error)
+
+	SetStatus(ctx context.Context, equipmentID uuid.UUID, status string) (*models.Equipment, error)
+	ScheduleMaintenance(ctx context.Context, maintenance *models.Maintenance) (*models.MaintenanceSchedule, error)
+	GetMaintenance(ctx context.Context, equipmentID uuid.UUID) ([]models.Maintenance, error)
+	CancelMaintenance(ctx context.Context, equipmentID uuid.UUID, maintenanceID int) error
+
+	CreateUnit(ctx context.Context, unit *models.EquipmentUnit) (*models.EquipmentUnit, error)
+	UpdateUnit(ctx context.Context, unit *models.EquipmentUnit) error
+	DeleteUnit(ctx context.Context, equipmentID uuid.UUID, unitID uuid.UUID) error
+	GetUnits(ctx context.Context, equipmentID uuid.UUID) ([]models.EquipmentUnit, error)
+
+	CreateKit(ctx context.Context, kit *models.Kit) (*models.Kit, error)
+	UpdateKit(ctx context.Context, kit *models.Kit) error
+	DeleteKit(ctx context.Context, kitID uuid.UUID) error
+	GetKit(ctx context.Context, kitID uuid.UUID) (*models.Kit, error)
+	GetKits(ctx context.Context) ([]models.Kit, error)
+	ReserveKit(ctx context.Context, kr *models.KitReservation) (*models.KitReservation, []models.UsersEquipment, error)
+	GetKitReservation(ctx context.Context, kitReservationID uuid.UUID) (*models.KitReservation, error)
+	CancelKitReservation(ctx context.Context, kitReservationID uuid.UUID) error
+
+	SetEquipmentType(ctx context.Context, equipmentType *models.EquipmentType) error
+	DeleteEquipmentType(ctx context.Context, equipmentType string) error
+	GetEquipmentType(ctx context.Context, equipmentType string) (*models.EquipmentType, error)
+	GetEquipmentTypes(ctx context.Context) ([]models.EquipmentType, error)
+
+	ProcessOverdue(ctx context.Context) error
+	SendReminders(ctx context.Context) error
+	JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) (*models.WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, entryID int) error
+	GetUserWaitlist(ctx context.Context) ([]models.WaitlistEntry, error)
+	GetEquipmentWaitlist(ctx context.Context, equipmentID uuid.UUID) ([]models.WaitlistEntry, error)
 }
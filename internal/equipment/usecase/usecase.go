@@ -1,71 +1,1898 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
 	"equiptrack/config"
+	"equiptrack/internal/audit"
 	"equiptrack/internal/equipment"
+	"equiptrack/internal/equipment/attributes"
+	"equiptrack/internal/equipment/label"
+	"equiptrack/internal/equipment/media"
+	"equiptrack/internal/equipment/policy"
+	"equiptrack/internal/equipment/recurrence"
+	httpErrors "equiptrack/internal/httpErrors"
 	"equiptrack/internal/models"
+	"equiptrack/internal/notification"
+	"equiptrack/internal/storage"
 	"equiptrack/internal/utils"
+	"equiptrack/internal/webhook"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 type equipmentUC struct {
 	cfg           *config.Config
 	equipmentRepo equipment.Repository
+	policies      *policy.Engine
+	notifier      notification.Notifier
+	publisher     webhook.Publisher
+	auditor       audit.Recorder
+	storage       storage.Storage
 	logger        *logrus.Logger
 }
 
-func NewEquipmentUseCase(cfg *config.Config, equipmentRepo equipment.Repository, log *logrus.Logger) equipment.UseCase {
-	return &equipmentUC{cfg: cfg, equipmentRepo: equipmentRepo, logger: log}
+func NewEquipmentUseCase(
+	cfg *config.Config,
+	equipmentRepo equipment.Repository,
+	notifier notification.Notifier,
+	publisher webhook.Publisher,
+	auditor audit.Recorder,
+	store storage.Storage,
+	log *logrus.Logger,
+) equipment.UseCase {
+	return &equipmentUC{
+		cfg:           cfg,
+		equipmentRepo: equipmentRepo,
+		policies:      policy.NewEngine(cfg),
+		notifier:      notifier,
+		publisher:     publisher,
+		auditor:       auditor,
+		storage:       store,
+		logger:        log,
+	}
 }
 
 func (u *equipmentUC) Create(ctx context.Context, equipment *models.Equipment) (*models.Equipment, error) {
-	newEquip, err := u.equipmentRepo.Create(ctx, equipment)
+	if err := u.checkLocation(ctx, equipment.LocationID); err != nil {
+		return nil, err
+	}
+	if err := u.checkAttributes(ctx, equipment.Type, equipment.Attributes); err != nil {
+		return nil, err
+	}
+	newEquip, err := u.equipmentRepo.Create(ctx, equipment, u.cfg.Assets.TagPrefix)
 	if err != nil {
+		if httpErrors.IsUniqueViolation(err) {
+			return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrAssetIdentifierTaken, err)
+		}
 		return nil, err
 	}
 
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityEquipment, newEquip.EquipmentID.String(), nil, newEquip)
+	u.publisher.Publish(ctx, models.EventEquipmentCreated, newEquip)
 	return newEquip, nil
 }
 
-func (u *equipmentUC) Update(ctx context.Context, equipment *models.Equipment) error {
-	return u.equipmentRepo.Update(ctx, equipment)
+func (u *equipmentUC) Update(ctx context.Context, update *models.EquipmentUpdate) error {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	equipment := &update.Equipment
+	before, err := u.equipmentRepo.GetByID(ctx, equipment.EquipmentID)
+	if err != nil {
+		return err
+	}
+	keepOmitted(update, before)
+	// equipment without a location given stays where it is
+	if equipment.LocationID == nil {
+		equipment.LocationID = before.LocationID
+	}
+	if err = u.checkLocation(ctx, equipment.LocationID); err != nil {
+		return err
+	}
+	// attributes not given are kept, they still have to fit the schema of a changed type
+	if len(equipment.Attributes) == 0 {
+		equipment.Attributes = before.Attributes
+	}
+	if err = u.checkAttributes(ctx, equipment.Type, equipment.Attributes); err != nil {
+		return err
+	}
+	if equipment.Quantity != 0 && equipment.Quantity < before.Quantity {
+		units, err := u.equipmentRepo.CountUnits(ctx, equipment.EquipmentID)
+		if err != nil {
+			return err
+		}
+		if equipment.Quantity < units {
+			return httpErrors.NewBadRequestError(fmt.Sprintf("%d units are registered, remove some first", units))
+		}
+		// a smaller pool must still hold the reservations already made
+		peak, err := u.equipmentRepo.GetBookedPeak(ctx, equipment.EquipmentID)
+		if err != nil {
+			return err
+		}
+		if equipment.Quantity < peak {
+			return httpErrors.NewBadRequestError(fmt.Sprintf(
+				"%d units are booked at the same time, move or cancel reservations first", peak))
+		}
+	}
+	if err = u.equipmentRepo.Update(ctx, equipment); err != nil {
+		if httpErrors.IsUniqueViolation(err) {
+			return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrAssetIdentifierTaken, err)
+		}
+		return err
+	}
+	// moved separately so the change lands in the location history
+	if _, err = u.equipmentRepo.MoveEquipment(ctx, &models.LocationChange{
+		EquipmentID:  equipment.EquipmentID,
+		ToLocationID: equipment.LocationID,
+		MovedBy:      user.UserID,
+	}); err != nil {
+		return err
+	}
+	if equipment.AssetTag == "" {
+		equipment.AssetTag = before.AssetTag
+	}
+	equipment.Status = before.Status
+	if equipment.Quantity == 0 {
+		equipment.Quantity = before.Quantity
+	}
+
+	u.auditor.Record(ctx, models.AuditUpdate, models.AuditEntityEquipment, equipment.EquipmentID.String(), before, equipment)
+	u.publisher.Publish(ctx, models.EventEquipmentUpdated, equipment)
+	return nil
+}
+
+// Fills the fields left out of the update with their stored value, an update which only
+// renames the equipment mustn't open team equipment to everyone or drop its approval
+func keepOmitted(update *models.EquipmentUpdate, before *models.Equipment) {
+	equipment := &update.Equipment
+	if equipment.Name == "" {
+		equipment.Name = before.Name
+	}
+	if equipment.ShortDescription == "" {
+		equipment.ShortDescription = before.ShortDescription
+	}
+	equipment.FullDescription = before.FullDescription
+	if update.FullDescription != nil {
+		equipment.FullDescription = *update.FullDescription
+	}
+	if equipment.TeamID == nil && !update.ClearTeam {
+		equipment.TeamID = before.TeamID
+	}
+	equipment.RequiresApproval = before.RequiresApproval
+	if update.RequiresApproval != nil {
+		equipment.RequiresApproval = *update.RequiresApproval
+	}
+	if equipment.CustodianID == nil && !update.ClearCustodian {
+		equipment.CustodianID = before.CustodianID
+	}
+	if equipment.Type == "" {
+		equipment.Type = before.Type
+	}
+	equipment.SerialNumber = before.SerialNumber
+	if update.SerialNumber != nil {
+		equipment.SerialNumber = *update.SerialNumber
+	}
 }
 
-func (u *equipmentUC) Delete(ctx context.Context, equipmentID uuid.UUID) error {
-	if err := u.equipmentRepo.Delete(ctx, equipmentID); err != nil {
+// Archives the equipment, it leaves the catalog and can't be reserved but its history is kept.
+// Equipment with reservations which haven't ended yet is archived only when forced, which
+// cancels those reservations
+func (u *equipmentUC) Delete(ctx context.Context, equipmentID uuid.UUID, force bool) error {
+	before, err := u.equipmentRepo.GetByID(ctx, equipmentID)
+	if err != nil {
+		return err
+	}
+	if before.ArchivedAt != nil {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentArchived, nil)
+	}
+	checkedOut, err := u.equipmentRepo.GetCheckedOutReservation(ctx, equipmentID)
+	if err != nil {
+		return err
+	}
+	if checkedOut != 0 {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentCheckedOut, nil)
+	}
+
+	cancelled, archived, err := u.equipmentRepo.Archive(ctx, equipmentID, force)
+	if err != nil {
 		return err
 	}
+	if !archived {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentHasUpcoming, nil)
+	}
+
+	for i := range cancelled {
+		reservation := &cancelled[i]
+		u.auditor.Record(ctx, models.AuditCancel, models.AuditEntityReservation, strconv.Itoa(reservation.Id), nil, reservation)
+		u.notify(ctx, models.NotificationReservationCancelled, reservation, before.Name, "equipment was archived")
+		u.publisher.Publish(ctx, models.EventReservationCancelled, reservation)
+	}
 
+	after := *before
+	now := time.Now()
+	after.ArchivedAt = &now
+	u.auditor.Record(ctx, models.AuditArchive, models.AuditEntityEquipment, equipmentID.String(), before, &after)
+	u.publisher.Publish(ctx, models.EventEquipmentDeleted, map[string]uuid.UUID{"equipment_id": equipmentID})
 	return nil
 }
 
+func (u *equipmentUC) Restore(ctx context.Context, equipmentID uuid.UUID) (*models.Equipment, error) {
+	before, err := u.equipmentRepo.GetByID(ctx, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if before.ArchivedAt == nil {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentNotArchived, nil)
+	}
+	if err = u.equipmentRepo.Restore(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+
+	restored := *before
+	restored.ArchivedAt = nil
+	u.auditor.Record(ctx, models.AuditRestore, models.AuditEntityEquipment, equipmentID.String(), before, &restored)
+	u.publisher.Publish(ctx, models.EventEquipmentRestored, &restored)
+	return &restored, nil
+}
+
+// Equipment with its photos and documents
 func (u *equipmentUC) GetByID(ctx context.Context, equipmentID uuid.UUID) (*models.Equipment, error) {
+	equipment, err := u.getByID(ctx, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if equipment.Attachments, err = u.equipmentRepo.GetAttachments(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	for i := range equipment.Attachments {
+		u.setAttachmentURLs(&equipment.Attachments[i])
+	}
+
+	return equipment, nil
+}
+
+func (u *equipmentUC) getByID(ctx context.Context, equipmentID uuid.UUID) (*models.Equipment, error) {
 	equipment, err := u.equipmentRepo.GetByID(ctx, equipmentID)
 	if err != nil {
 		return nil, err
 	}
+	if err = u.checkAccess(ctx, equipment); err != nil {
+		return nil, err
+	}
 
 	return equipment, nil
 }
 
-func (u *equipmentUC) GetEquipments(ctx context.Context, pq *utils.PaginationQuery) (*models.EquipmentList, error) {
-	return u.equipmentRepo.GetEquipments(ctx, pq)
+// Resolves a scanned or typed code: an asset tag, a serial number, an equipment ID
+// or a label URL carrying one of them in its code parameter
+func (u *equipmentUC) Lookup(ctx context.Context, code string) (*models.Equipment, error) {
+	code = strings.TrimSpace(code)
+	if parsed, err := url.Parse(code); err == nil && parsed.Scheme != "" {
+		if c := parsed.Query().Get("code"); c != "" {
+			code = c
+		}
+	}
+	if code == "" {
+		return nil, httpErrors.NewBadRequestError("code is required")
+	}
+
+	equipmentID, err := uuid.Parse(code)
+	if err != nil {
+		if equipmentID, err = u.equipmentRepo.GetIDByCode(ctx, code); err != nil {
+			return nil, err
+		}
+	}
+	return u.GetByID(ctx, equipmentID)
+}
+
+// QR code label of the equipment in the given format, encoding its label URL
+func (u *equipmentUC) GetQRCode(ctx context.Context, equipmentID uuid.UUID, format string, size int) ([]byte, error) {
+	equipment, err := u.getByID(ctx, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		size = label.DefaultSize
+	}
+	if size < 0 || size > label.MaxSize {
+		return nil, httpErrors.NewBadRequestError(fmt.Sprintf("size must be between 1 and %d", label.MaxSize))
+	}
+
+	content := u.labelURL(equipment)
+	switch format {
+	case label.FormatPNG, "":
+		return label.PNG(content, size)
+	case label.FormatSVG:
+		return label.SVG(content, size)
+	default:
+		return nil, httpErrors.NewBadRequestError("format must be png or svg")
+	}
+}
+
+// PDF sheet with a label per equipment, in the requested order
+func (u *equipmentUC) GetLabelSheet(ctx context.Context, request *models.LabelSheetRequest) ([]byte, error) {
+	template, ok := label.FindTemplate(u.cfg, request.Template)
+	if !ok {
+		return nil, httpErrors.NewBadRequestError("unknown label template " + request.Template)
+	}
+	if request.Skip >= template.Columns*template.Rows {
+		return nil, httpErrors.NewBadRequestError("skip must be less than the labels on a sheet")
+	}
+
+	items := make([]label.Item, 0, len(request.EquipmentIDs))
+	for _, equipmentID := range request.EquipmentIDs {
+		equipment, err := u.getByID(ctx, equipmentID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, label.Item{Name: equipment.Name, Tag: equipment.AssetTag, URL: u.labelURL(equipment)})
+	}
+
+	sheet, err := label.Sheet(template, items, request.Skip)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return sheet, nil
+}
+
+func (u *equipmentUC) GetLabelTemplates() []config.LabelTemplate {
+	return label.Templates(u.cfg)
+}
+
+func (u *equipmentUC) labelURL(equipment *models.Equipment) string {
+	code := equipment.AssetTag
+	if code == "" {
+		code = equipment.EquipmentID.String()
+	}
+	if u.cfg.Assets.LabelURL != "" {
+		return fmt.Sprintf(u.cfg.Assets.LabelURL, url.QueryEscape(code))
+	}
+	return fmt.Sprintf("%s/api/equipment/lookup?code=%s", u.cfg.Server.PublicURL, url.QueryEscape(code))
+}
+
+// Lists the catalog, narrowed by the location and attribute filters. Visibility is
+// decided by the user, not the filter
+func (u *equipmentUC) GetEquipments(
+	ctx context.Context,
+	pq *utils.PaginationQuery,
+	filter *models.EquipmentFilter,
+) (*models.EquipmentList, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.equipmentRepo.GetEquipments(ctx, pq, &models.EquipmentFilter{
+		ViewerID:   user.UserID,
+		ViewAll:    user.IsAdmin(),
+		LocationID: filter.LocationID,
+		Attributes: filter.Attributes,
+	})
+}
+
+// Archived equipment is listed for admins only, to find what to restore
+func (u *equipmentUC) GetArchivedEquipments(ctx context.Context, pq *utils.PaginationQuery) (*models.EquipmentList, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.equipmentRepo.GetEquipments(ctx, pq, &models.EquipmentFilter{
+		ViewerID: user.UserID,
+		ViewAll:  true,
+		Archived: true,
+	})
 }
 
+// Bookings of a user are listed only to the user and admins
 func (u *equipmentUC) GetUserEquipments(
 	ctx context.Context,
 	pq *utils.PaginationQuery,
 	userId uuid.UUID) (*models.EquipmentList, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserID != userId && !user.IsAdmin() {
+		return nil, httpErrors.NewForbiddenError("only the user and admins may list the user's reservations")
+	}
 	return u.equipmentRepo.GetUserEquipments(ctx, pq, userId)
 }
 
 func (u *equipmentUC) GetReservationInfo(ctx context.Context, equipmentId uuid.UUID) (*models.ReservationInfoResponse, error) {
+	if _, err := u.getByID(ctx, equipmentId); err != nil {
+		return nil, err
+	}
 	return u.equipmentRepo.GetReservationInfo(ctx, equipmentId)
 }
 
+// Reservations of equipment requiring approval are created pending, unless the
+// requester could approve them anyway
 func (u *equipmentUC) ReserveEquipment(ctx context.Context, reservation *models.UsersEquipment) (bool, error) {
-	return u.equipmentRepo.ReserveEquipment(ctx, reservation)
+	equipment, err := u.getByID(ctx, reservation.EquipmentID)
+	if err != nil {
+		return false, err
+	}
+	if err = checkReservable(equipment); err != nil {
+		return false, err
+	}
+	if reservation.Quantity, err = checkQuantity(equipment, reservation.Quantity); err != nil {
+		return false, err
+	}
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err = u.checkPolicies(ctx, user, equipment, reservation, nil, nil); err != nil {
+		return false, err
+	}
+
+	reservation.Status = models.ReservationConfirmed
+	if equipment.RequiresApproval && !equipment.CanApprove(user) {
+		reservation.Status = models.ReservationPending
+	}
+
+	created, err := u.equipmentRepo.ReserveEquipment(ctx, reservation)
+	if err != nil || !created {
+		return created, err
+	}
+	u.auditor.Record(ctx, models.AuditReserve, models.AuditEntityReservation, strconv.Itoa(reservation.Id), nil, reservation)
+	u.notify(ctx, models.NotificationReservationCreated, reservation, equipment.Name, "")
+	u.publisher.Publish(ctx, models.EventReservationCreated, reservation)
+	return true, nil
+}
+
+// Archived equipment and equipment out of service can't be booked
+func checkReservable(equipment *models.Equipment) error {
+	if equipment.ArchivedAt != nil {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentArchived, nil)
+	}
+	if equipment.Status != models.EquipmentActive {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentOutOfService, nil)
+	}
+	return nil
+}
+
+// Units to book, one unless given. More than the pool holds never fit
+func checkQuantity(equipment *models.Equipment, quantity int) (int, error) {
+	if quantity == 0 {
+		return 1, nil
+	}
+	if quantity < 0 {
+		return 0, httpErrors.NewBadRequestError("quantity can't be negative")
+	}
+	if quantity > equipment.Quantity {
+		return 0, httpErrors.NewBadRequestError(fmt.Sprintf("only %d units of the equipment exist", equipment.Quantity))
+	}
+	return quantity, nil
+}
+
+// Evaluate every booking policy applying to the user and equipment type. Stored reservations
+// the request replaces aren't counted in usage, while reservations it accepted earlier but
+// hasn't stored yet are, so a series or kit can't exceed limits its parts pass one by one
+func (u *equipmentUC) checkPolicies(
+	ctx context.Context,
+	user *models.User,
+	equipment *models.Equipment,
+	reservation *models.UsersEquipment,
+	replaced []models.UsersEquipment,
+	pending []policy.Pending,
+) error {
+	now := time.Now()
+	usage := make(map[string]*models.BookingUsage)
+
+	for _, p := range u.policies.Applicable(user, equipment) {
+		// usage of type specific policies is counted only over equipment of that type
+		typeUsage, ok := usage[p.EquipmentType]
+		if !ok {
+			var err error
+			typeUsage, err = u.equipmentRepo.GetBookingUsage(
+				ctx, user.UserID, policy.MonthStart(reservation.ReservationStart), p.EquipmentType,
+			)
+			if err != nil {
+				return err
+			}
+			usage[p.EquipmentType] = typeUsage
+		}
+
+		monthStart := policy.MonthStart(reservation.ReservationStart)
+		checked := *typeUsage
+		for i := range replaced {
+			checked = policy.Without(checked, &replaced[i], monthStart, now)
+		}
+		checked = policy.With(checked, pending, p.EquipmentType, monthStart, now)
+		if err := u.policies.Check(p, reservation, &checked, now); err != nil {
+			return httpErrors.NewRestError(http.StatusUnprocessableEntity, err.Error(), err)
+		}
+	}
+	return nil
+}
+
+func (u *equipmentUC) GetPendingReservations(ctx context.Context) ([]models.UsersEquipment, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.equipmentRepo.GetPendingReservations(ctx, user)
+}
+
+func (u *equipmentUC) ApproveReservation(ctx context.Context, reservationID int) error {
+	reservation, equipment, err := u.getPendingForApprover(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.UpdateReservationStatus(
+		ctx, reservationID, models.ReservationPending, models.ReservationConfirmed, "",
+	); err != nil {
+		return err
+	}
+
+	before := *reservation
+	reservation.Status = models.ReservationConfirmed
+	u.auditor.Record(ctx, models.AuditApprove, models.AuditEntityReservation, strconv.Itoa(reservationID), &before, reservation)
+	u.notify(ctx, models.NotificationReservationApproved, reservation, equipment.Name, "")
+	u.publisher.Publish(ctx, models.EventReservationApproved, reservation)
+	return nil
+}
+
+func (u *equipmentUC) RejectReservation(ctx context.Context, reservationID int, reason string) error {
+	reservation, equipment, err := u.getPendingForApprover(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.UpdateReservationStatus(
+		ctx, reservationID, models.ReservationPending, models.ReservationRejected, reason,
+	); err != nil {
+		return err
+	}
+
+	before := *reservation
+	reservation.Status = models.ReservationRejected
+	reservation.RejectionReason = reason
+	u.auditor.Record(ctx, models.AuditReject, models.AuditEntityReservation, strconv.Itoa(reservationID), &before, reservation)
+	u.notify(ctx, models.NotificationReservationRejected, reservation, equipment.Name, reason)
+	u.publisher.Publish(ctx, models.EventReservationRejected, reservation)
+
+	u.processWaitlist(ctx, reservation.EquipmentID, reservation.ReservationStart, reservation.ReservationEnd)
+	return nil
+}
+
+// Owners may cancel their reservations, admins and the custodian any reservation of the equipment
+func (u *equipmentUC) CancelReservation(ctx context.Context, reservationID int) error {
+	reservation, err := u.getActiveForOwner(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	checkedOut, err := u.equipmentRepo.GetCheckedOutReservation(ctx, reservation.EquipmentID)
+	if err != nil {
+		return err
+	}
+	if checkedOut == reservationID {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentCheckedOut, nil)
+	}
+	if err = u.equipmentRepo.UpdateReservationStatus(
+		ctx, reservationID, reservation.Status, models.ReservationCancelled, "",
+	); err != nil {
+		return err
+	}
+
+	before := *reservation
+	reservation.Status = models.ReservationCancelled
+	u.auditor.Record(ctx, models.AuditCancel, models.AuditEntityReservation, strconv.Itoa(reservationID), &before, reservation)
+	if equipment, err := u.equipmentRepo.GetByID(ctx, reservation.EquipmentID); err == nil {
+		u.notify(ctx, models.NotificationReservationCancelled, reservation, equipment.Name, "")
+	}
+	u.publisher.Publish(ctx, models.EventReservationCancelled, reservation)
+	u.processWaitlist(ctx, reservation.EquipmentID, reservation.ReservationStart, reservation.ReservationEnd)
+	return nil
+}
+
+// Moves reservation end earlier, the freed tail is offered to the waitlist
+func (u *equipmentUC) ShortenReservation(ctx context.Context, reservationID int, end time.Time) error {
+	reservation, err := u.getActiveForOwner(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	if !end.After(reservation.ReservationStart) || !end.Before(reservation.ReservationEnd) {
+		return httpErrors.NewBadRequestError("new end must be between reservation start and current end")
+	}
+	if err = u.equipmentRepo.ShortenReservation(ctx, reservationID, end); err != nil {
+		return err
+	}
+	after := *reservation
+	after.ReservationEnd = end
+	u.auditor.Record(ctx, models.AuditShorten, models.AuditEntityReservation, strconv.Itoa(reservationID), reservation, &after)
+	if equipment, err := u.equipmentRepo.GetByID(ctx, reservation.EquipmentID); err == nil {
+		u.notify(ctx, models.NotificationReservationShortened, &after, equipment.Name, "")
+	}
+	u.publisher.Publish(ctx, models.EventReservationShortened, &after)
+
+	u.processWaitlist(ctx, reservation.EquipmentID, end, reservation.ReservationEnd)
+	return nil
+}
+
+// Moves a single reservation, e.g. one occurrence of a series. Reservations needing
+// approval go back to pending unless the user could approve them
+func (u *equipmentUC) RescheduleReservation(
+	ctx context.Context,
+	reservationID int,
+	start time.Time,
+	end time.Time,
+) ([]models.UsersEquipment, error) {
+	if !end.After(start) {
+		return nil, httpErrors.NewBadRequestError("reservation end must be after its start")
+	}
+	reservation, err := u.getActiveForOwner(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := u.prepareReschedule(ctx, reservation.EquipmentID, []models.UsersEquipment{*reservation},
+		func(ue models.UsersEquipment) (time.Time, time.Time) { return start, end })
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := u.equipmentRepo.RescheduleReservations(ctx, reservation.EquipmentID, moved)
+	if err != nil || len(conflicts) > 0 {
+		return conflicts, err
+	}
+	u.auditor.Record(ctx, models.AuditReschedule, models.AuditEntityReservation, strconv.Itoa(reservationID), reservation, &moved[0])
+
+	u.processWaitlist(ctx, reservation.EquipmentID, reservation.ReservationStart, reservation.ReservationEnd)
+	return conflicts, nil
+}
+
+// Check the user may book the new windows and set the status each moved reservation gets
+func (u *equipmentUC) prepareReschedule(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	reservations []models.UsersEquipment,
+	window func(ue models.UsersEquipment) (time.Time, time.Time),
+) ([]models.UsersEquipment, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	equipment, err := u.equipmentRepo.GetByID(ctx, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkReservable(equipment); err != nil {
+		return nil, err
+	}
+
+	moved := make([]models.UsersEquipment, 0, len(reservations))
+	pending := make([]policy.Pending, 0, len(reservations))
+	for i := range reservations {
+		ue := reservations[i]
+		ue.ReservationStart, ue.ReservationEnd = window(reservations[i])
+		if equipment.RequiresApproval && !equipment.CanApprove(user) {
+			ue.Status = models.ReservationPending
+		}
+		// approvers moving someone else's reservation aren't bound by the owner's policies.
+		// Reservations moved so far replace their stored windows
+		if ue.UserID == user.UserID {
+			if err := u.checkPolicies(ctx, user, equipment, &ue, reservations[:i+1], pending); err != nil {
+				return nil, err
+			}
+			pending = append(pending, policy.Pending{Reservation: ue, EquipmentType: equipment.Type})
+		}
+		moved = append(moved, ue)
+	}
+	return moved, nil
+}
+
+// Creates every occurrence of the series or, when some of them collide with other
+// reservations, none and returns the colliding ones
+func (u *equipmentUC) CreateSeries(
+	ctx context.Context,
+	series *models.ReservationSeries,
+) (*models.ReservationSeries, []models.UsersEquipment, error) {
+	if !series.ReservationEnd.After(series.ReservationStart) {
+		return nil, nil, httpErrors.NewBadRequestError("reservation end must be after its start")
+	}
+	rule, err := recurrence.Parse(series.Rule)
+	if err != nil {
+		return nil, nil, httpErrors.NewBadRequestError(err.Error())
+	}
+	starts, err := rule.Occurrences(series.ReservationStart)
+	if err != nil {
+		return nil, nil, httpErrors.NewBadRequestError(err.Error())
+	}
+
+	equipment, err := u.getByID(ctx, series.EquipmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = checkReservable(equipment); err != nil {
+		return nil, nil, err
+	}
+	if series.Quantity, err = checkQuantity(equipment, series.Quantity); err != nil {
+		return nil, nil, err
+	}
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	series.UserID = user.UserID
+
+	status := models.ReservationConfirmed
+	if equipment.RequiresApproval && !equipment.CanApprove(user) {
+		status = models.ReservationPending
+	}
+	duration := series.ReservationEnd.Sub(series.ReservationStart)
+	occurrences := make([]models.UsersEquipment, 0, len(starts))
+	pending := make([]policy.Pending, 0, len(starts))
+	for _, start := range starts {
+		occurrence := models.UsersEquipment{
+			UserID:           user.UserID,
+			EquipmentID:      series.EquipmentID,
+			ReservationStart: start,
+			ReservationEnd:   start.Add(duration),
+			Status:           status,
+			Quantity:         series.Quantity,
+		}
+		if err := u.checkPolicies(ctx, user, equipment, &occurrence, nil, pending); err != nil {
+			return nil, nil, err
+		}
+		occurrences = append(occurrences, occurrence)
+		pending = append(pending, policy.Pending{Reservation: occurrence, EquipmentType: equipment.Type})
+	}
+
+	conflicts, err := u.equipmentRepo.CreateSeries(ctx, series, occurrences)
+	if err != nil || len(conflicts) > 0 {
+		return nil, conflicts, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntitySeries, series.SeriesID.String(), nil, series)
+	return series, nil, nil
+}
+
+func (u *equipmentUC) GetSeries(ctx context.Context, seriesID uuid.UUID) (*models.ReservationSeries, error) {
+	return u.getSeriesForOwner(ctx, seriesID)
+}
+
+// Applies the time of day and duration of the given window to every occurrence
+// which hasn't started yet
+func (u *equipmentUC) UpdateSeries(
+	ctx context.Context,
+	seriesID uuid.UUID,
+	start time.Time,
+	end time.Time,
+) ([]models.UsersEquipment, error) {
+	if !end.After(start) {
+		return nil, httpErrors.NewBadRequestError("reservation end must be after its start")
+	}
+	series, err := u.getSeriesForOwner(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	upcoming := make([]models.UsersEquipment, 0, len(series.Occurrences))
+	for _, ue := range series.Occurrences {
+		active := ue.Status == models.ReservationPending || ue.Status == models.ReservationConfirmed
+		if active && ue.ReservationStart.After(now) {
+			upcoming = append(upcoming, ue)
+		}
+	}
+	if len(upcoming) == 0 {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrReservationNotActive, nil)
+	}
+
+	duration := end.Sub(start)
+	moved, err := u.prepareReschedule(ctx, series.EquipmentID, upcoming,
+		func(ue models.UsersEquipment) (time.Time, time.Time) {
+			s := ue.ReservationStart.In(start.Location())
+			newStart := time.Date(s.Year(), s.Month(), s.Day(),
+				start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+			return newStart, newStart.Add(duration)
+		})
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := u.equipmentRepo.RescheduleReservations(ctx, series.EquipmentID, moved)
+	if err != nil || len(conflicts) > 0 {
+		return conflicts, err
+	}
+	u.auditor.Record(ctx, models.AuditReschedule, models.AuditEntitySeries, seriesID.String(),
+		map[string]interface{}{"occurrences": upcoming}, map[string]interface{}{"occurrences": moved})
+
+	for _, ue := range upcoming {
+		u.processWaitlist(ctx, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd)
+	}
+	return conflicts, nil
+}
+
+// Cancels every occurrence which hasn't ended yet, past ones are kept for history
+func (u *equipmentUC) CancelSeries(ctx context.Context, seriesID uuid.UUID) error {
+	series, err := u.getSeriesForOwner(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	cancelled, err := u.equipmentRepo.CancelSeries(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditCancel, models.AuditEntitySeries, seriesID.String(), series,
+		map[string]interface{}{"cancelled": cancelled})
+	for _, ue := range cancelled {
+		u.processWaitlist(ctx, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd)
+	}
+	return nil
+}
+
+func (u *equipmentUC) getSeriesForOwner(ctx context.Context, seriesID uuid.UUID) (*models.ReservationSeries, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := u.equipmentRepo.GetSeries(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.UserID != user.UserID {
+		equipment, err := u.equipmentRepo.GetByID(ctx, series.EquipmentID)
+		if err != nil {
+			return nil, err
+		}
+		if !equipment.CanApprove(user) {
+			return nil, httpErrors.NewForbiddenError("only the owner, admins and the custodian may manage the series")
+		}
+	}
+	return series, nil
+}
+
+func (u *equipmentUC) getActiveForOwner(ctx context.Context, reservationID int) (*models.UsersEquipment, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := u.equipmentRepo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.UserID != user.UserID {
+		equipment, err := u.equipmentRepo.GetByID(ctx, reservation.EquipmentID)
+		if err != nil {
+			return nil, err
+		}
+		if !equipment.CanApprove(user) {
+			return nil, httpErrors.NewForbiddenError("only the owner, admins and the custodian may change reservations")
+		}
+	}
+	if reservation.Status != models.ReservationPending && reservation.Status != models.ReservationConfirmed {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrReservationNotActive, nil)
+	}
+	return reservation, nil
+}
+
+// Hands the equipment over to the reservation owner. Only confirmed reservations which
+// haven't ended can be checked out, and only after the previous holder returned the item
+func (u *equipmentUC) CheckOut(ctx context.Context, reservationID int, handover *models.Handover) (*models.Handover, error) {
+	reservation, err := u.getForHandover(ctx, reservationID, handover)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != models.ReservationConfirmed || !reservation.ReservationEnd.After(time.Now()) {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrReservationNotActive, nil)
+	}
+
+	handover.Kind = models.HandoverCheckOut
+	created, err := u.equipmentRepo.CreateHandover(ctx, handover)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentCheckedOut, nil)
+	}
+	u.auditor.Record(ctx, models.AuditCheckOut, models.AuditEntityReservation, strconv.Itoa(reservationID), nil, handover)
+	return handover, nil
+}
+
+// Records return of the equipment checked out on the reservation, also late ones
+func (u *equipmentUC) CheckIn(ctx context.Context, reservationID int, handover *models.Handover) (*models.Handover, error) {
+	if _, err := u.getForHandover(ctx, reservationID, handover); err != nil {
+		return nil, err
+	}
+	if err := u.checkLocation(ctx, handover.LocationID); err != nil {
+		return nil, err
+	}
+
+	handover.Kind = models.HandoverCheckIn
+	created, err := u.equipmentRepo.CreateHandover(ctx, handover)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentNotOut, nil)
+	}
+	u.auditor.Record(ctx, models.AuditCheckIn, models.AuditEntityReservation, strconv.Itoa(reservationID), nil, handover)
+	return handover, nil
+}
+
+// Handovers are recorded by the owner, admins or the custodian, who is stored as
+// the one handling it
+func (u *equipmentUC) getForHandover(
+	ctx context.Context,
+	reservationID int,
+	handover *models.Handover,
+) (*models.UsersEquipment, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := u.equipmentRepo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.UserID != user.UserID {
+		equipment, err := u.equipmentRepo.GetByID(ctx, reservation.EquipmentID)
+		if err != nil {
+			return nil, err
+		}
+		if !equipment.CanApprove(user) {
+			return nil, httpErrors.NewForbiddenError("only the owner, admins and the custodian may record handovers")
+		}
+	}
+
+	handover.ReservationID = reservation.Id
+	handover.EquipmentID = reservation.EquipmentID
+	handover.UserID = reservation.UserID
+	handover.HandledBy = user.UserID
+	return reservation, nil
+}
+
+func (u *equipmentUC) GetReservationHandovers(ctx context.Context, reservationID int) ([]models.Handover, error) {
+	reservation, err := u.equipmentRepo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = u.getByID(ctx, reservation.EquipmentID); err != nil {
+		return nil, err
+	}
+	return u.equipmentRepo.GetReservationHandovers(ctx, reservationID)
+}
+
+func (u *equipmentUC) GetEquipmentHandovers(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	pq *utils.PaginationQuery,
+) ([]models.Handover, error) {
+	if _, err := u.getByID(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	return u.equipmentRepo.GetEquipmentHandovers(ctx, equipmentID, pq)
+}
+
+// Changes the lifecycle status, equipment which isn't active can't be reserved. Existing
+// reservations are kept, maintenance windows block the time it's away
+func (u *equipmentUC) SetStatus(ctx context.Context, equipmentID uuid.UUID, status string) (*models.Equipment, error) {
+	before, err := u.equipmentRepo.GetByID(ctx, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if before.ArchivedAt != nil {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentArchived, nil)
+	}
+	if err = u.equipmentRepo.SetStatus(ctx, equipmentID, status); err != nil {
+		return nil, err
+	}
+
+	after := *before
+	after.Status = status
+	u.auditor.Record(ctx, models.AuditUpdate, models.AuditEntityEquipment, equipmentID.String(), before, &after)
+	u.publisher.Publish(ctx, models.EventEquipmentUpdated, &after)
+	return &after, nil
+}
+
+// Blocks the window for reservations. Bookings already in it are kept, their owners
+// are notified to move or cancel them
+func (u *equipmentUC) ScheduleMaintenance(
+	ctx context.Context,
+	maintenance *models.Maintenance,
+) (*models.MaintenanceSchedule, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !maintenance.WindowEnd.After(maintenance.WindowStart) {
+		return nil, httpErrors.NewBadRequestError("maintenance end must be after its start")
+	}
+	if !maintenance.WindowEnd.After(time.Now()) {
+		return nil, httpErrors.NewBadRequestError("maintenance can't end in the past")
+	}
+	equipment, err := u.equipmentRepo.GetByID(ctx, maintenance.EquipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if equipment.ArchivedAt != nil {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentArchived, nil)
+	}
+
+	maintenance.CreatedBy = user.UserID
+	collisions, err := u.equipmentRepo.ScheduleMaintenance(ctx, maintenance)
+	if err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityMaintenance, strconv.Itoa(maintenance.MaintenanceID),
+		nil, maintenance)
+	for i := range collisions {
+		u.notify(ctx, models.NotificationMaintenanceScheduled, &collisions[i], equipment.Name, maintenance.Reason)
+	}
+	return &models.MaintenanceSchedule{Maintenance: maintenance, Collisions: collisions}, nil
+}
+
+func (u *equipmentUC) GetMaintenance(ctx context.Context, equipmentID uuid.UUID) ([]models.Maintenance, error) {
+	if _, err := u.getByID(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	return u.equipmentRepo.GetEquipmentMaintenance(ctx, equipmentID)
+}
+
+// Frees the maintenance window, waitlisted users get their chance at it
+func (u *equipmentUC) CancelMaintenance(ctx context.Context, equipmentID uuid.UUID, maintenanceID int) error {
+	maintenance, err := u.equipmentRepo.GetMaintenance(ctx, equipmentID, maintenanceID)
+	if err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.CancelMaintenance(ctx, equipmentID, maintenanceID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditCancel, models.AuditEntityMaintenance, strconv.Itoa(maintenanceID), maintenance, nil)
+	u.processWaitlist(ctx, equipmentID, maintenance.WindowStart, maintenance.WindowEnd)
+	return nil
+}
+
+// Registers an individually tracked unit, a pool holds at most its quantity of units
+func (u *equipmentUC) CreateUnit(ctx context.Context, unit *models.EquipmentUnit) (*models.EquipmentUnit, error) {
+	if _, err := u.equipmentRepo.GetByID(ctx, unit.EquipmentID); err != nil {
+		return nil, err
+	}
+	created, err := u.equipmentRepo.CreateUnit(ctx, unit, u.cfg.Assets.TagPrefix)
+	if err != nil {
+		if httpErrors.IsUniqueViolation(err) {
+			return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrAssetIdentifierTaken, err)
+		}
+		return nil, err
+	}
+	if !created {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrPoolFull, nil)
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityUnit, unit.UnitID.String(), nil, unit)
+	return unit, nil
+}
+
+func (u *equipmentUC) UpdateUnit(ctx context.Context, unit *models.EquipmentUnit) error {
+	before, err := u.equipmentRepo.GetUnit(ctx, unit.EquipmentID, unit.UnitID)
+	if err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.UpdateUnit(ctx, unit); err != nil {
+		if httpErrors.IsUniqueViolation(err) {
+			return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrAssetIdentifierTaken, err)
+		}
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditUpdate, models.AuditEntityUnit, unit.UnitID.String(), before, unit)
+	return nil
+}
+
+func (u *equipmentUC) DeleteUnit(ctx context.Context, equipmentID uuid.UUID, unitID uuid.UUID) error {
+	before, err := u.equipmentRepo.GetUnit(ctx, equipmentID, unitID)
+	if err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.DeleteUnit(ctx, equipmentID, unitID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityUnit, unitID.String(), before, nil)
+	return nil
+}
+
+func (u *equipmentUC) GetUnits(ctx context.Context, equipmentID uuid.UUID) ([]models.EquipmentUnit, error) {
+	if _, err := u.getByID(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	return u.equipmentRepo.GetUnits(ctx, equipmentID)
+}
+
+// Where the equipment was moved over time, latest first
+func (u *equipmentUC) GetLocationHistory(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	pq *utils.PaginationQuery,
+) ([]models.LocationChange, error) {
+	if _, err := u.getByID(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	return u.equipmentRepo.GetLocationHistory(ctx, equipmentID, pq)
+}
+
+func (u *equipmentUC) checkLocation(ctx context.Context, locationID *uuid.UUID) error {
+	if locationID == nil {
+		return nil
+	}
+	exists, err := u.equipmentRepo.LocationExists(ctx, *locationID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return httpErrors.NewBadRequestError("location not found")
+	}
+	return nil
+}
+
+// Attributes must be a JSON object, valid against the schema of the type when it has one
+func (u *equipmentUC) checkAttributes(ctx context.Context, equipmentType string, attrs json.RawMessage) error {
+	if _, err := attributes.Decode(attrs); err != nil {
+		return httpErrors.NewBadRequestError(err.Error())
+	}
+	if equipmentType == "" {
+		return nil
+	}
+	t, err := u.equipmentRepo.GetEquipmentType(ctx, equipmentType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	schema, err := attributes.Parse(t.Schema)
+	if err != nil {
+		return err
+	}
+	if err = schema.Validate(attrs); err != nil {
+		return httpErrors.NewBadRequestError(err.Error())
+	}
+	return nil
+}
+
+// Waitlist is only for windows which are actually taken, free ones should be reserved directly
+func (u *equipmentUC) JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) (*models.WaitlistEntry, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.WindowEnd.After(entry.WindowStart) || !entry.WindowEnd.After(time.Now()) {
+		return nil, httpErrors.NewBadRequestError("window end must be in the future and after its start")
+	}
+	equipment, err := u.getByID(ctx, entry.EquipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkReservable(equipment); err != nil {
+		return nil, err
+	}
+
+	busy, err := u.equipmentRepo.IsEquipmentReservedAt(ctx, entry.EquipmentID, entry.WindowStart, entry.WindowEnd, 1)
+	if err != nil {
+		return nil, err
+	}
+	if !busy {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrEquipmentAvailable, nil)
+	}
+
+	entry.UserID = user.UserID
+	if err = u.equipmentRepo.JoinWaitlist(ctx, entry); err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditJoinWaitlist, models.AuditEntityWaitlist, strconv.Itoa(entry.Id), nil, entry)
+	return entry, nil
+}
+
+func (u *equipmentUC) LeaveWaitlist(ctx context.Context, entryID int) error {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry, err := u.equipmentRepo.GetWaitlistEntry(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if entry.UserID != user.UserID && !user.IsAdmin() {
+		return httpErrors.NewForbiddenError("only the owner and admins may remove waitlist entries")
+	}
+	if entry.Status != models.WaitlistWaiting {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrWaitlistNotWaiting, nil)
+	}
+	if err = u.equipmentRepo.UpdateWaitlistStatus(
+		ctx, entryID, models.WaitlistWaiting, models.WaitlistCancelled, nil,
+	); err != nil {
+		return err
+	}
+
+	after := *entry
+	after.Status = models.WaitlistCancelled
+	u.auditor.Record(ctx, models.AuditLeaveWaitlist, models.AuditEntityWaitlist, strconv.Itoa(entryID), entry, &after)
+	return nil
+}
+
+func (u *equipmentUC) GetUserWaitlist(ctx context.Context) ([]models.WaitlistEntry, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.equipmentRepo.GetUserWaitlist(ctx, user.UserID)
+}
+
+// Approvers of the equipment see the whole waitlist, other users only their own entries
+func (u *equipmentUC) GetEquipmentWaitlist(ctx context.Context, equipmentID uuid.UUID) ([]models.WaitlistEntry, error) {
+	equipment, err := u.getByID(ctx, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := u.equipmentRepo.GetEquipmentWaitlist(ctx, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if equipment.CanApprove(user) {
+		return entries, nil
+	}
+
+	own := make([]models.WaitlistEntry, 0)
+	for _, entry := range entries {
+		if entry.UserID == user.UserID {
+			own = append(own, entry)
+		}
+	}
+	return own, nil
+}
+
+// Assign the freed window to waiters in the order they joined. A waiter is skipped when
+// their window is still partly taken, they lost access to the equipment or booking
+// policies don't allow the reservation anymore. Failures are only logged, the change
+// which freed the window is already done
+func (u *equipmentUC) processWaitlist(ctx context.Context, equipmentID uuid.UUID, start time.Time, end time.Time) {
+	entries, err := u.equipmentRepo.GetWaitingEntries(ctx, equipmentID, start, end)
+	if err != nil {
+		u.logger.Errorf("equipmentUC.processWaitlist.GetWaitingEntries: %v", err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	equipment, err := u.equipmentRepo.GetByID(ctx, equipmentID)
+	if err != nil {
+		u.logger.Errorf("equipmentUC.processWaitlist.GetByID: %v", err)
+		return
+	}
+	if checkReservable(equipment) != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		waiter := &models.User{UserID: entry.UserID, Role: entry.Role}
+		if err := u.canAccess(ctx, equipment, waiter); err != nil {
+			continue
+		}
+
+		reservation := &models.UsersEquipment{
+			UserID:           entry.UserID,
+			EquipmentID:      equipmentID,
+			ReservationStart: entry.WindowStart,
+			ReservationEnd:   entry.WindowEnd,
+			Status:           models.ReservationConfirmed,
+			Quantity:         1,
+		}
+		if err := u.checkPolicies(ctx, waiter, equipment, reservation, nil, nil); err != nil {
+			u.logger.Infof("waitlist entry %d skipped: %v", entry.Id, err)
+			continue
+		}
+		if equipment.RequiresApproval && !equipment.CanApprove(waiter) {
+			reservation.Status = models.ReservationPending
+		}
+
+		created, err := u.equipmentRepo.ReserveEquipment(ctx, reservation)
+		if err != nil {
+			u.logger.Errorf("equipmentUC.processWaitlist.ReserveEquipment: %v", err)
+			return
+		}
+		if !created {
+			continue
+		}
+		if err := u.equipmentRepo.UpdateWaitlistStatus(
+			ctx, entry.Id, models.WaitlistWaiting, models.WaitlistAssigned, &reservation.Id,
+		); err != nil {
+			u.logger.Errorf("equipmentUC.processWaitlist.UpdateWaitlistStatus: %v", err)
+		}
+		u.auditor.Record(ctx, models.AuditReserve, models.AuditEntityReservation, strconv.Itoa(reservation.Id), nil, reservation)
+		u.notify(ctx, models.NotificationReservationCreated, reservation, equipment.Name, "")
+		u.publisher.Publish(ctx, models.EventReservationCreated, reservation)
+	}
+}
+
+func (u *equipmentUC) getPendingForApprover(
+	ctx context.Context,
+	reservationID int,
+) (*models.UsersEquipment, *models.Equipment, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reservation, err := u.equipmentRepo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	equipment, err := u.equipmentRepo.GetByID(ctx, reservation.EquipmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !equipment.CanApprove(user) {
+		return nil, nil, httpErrors.NewForbiddenError("only admins and the custodian may decide on reservations")
+	}
+	if reservation.Status != models.ReservationPending {
+		return nil, nil, httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrReservationNotPending, nil)
+	}
+	return reservation, equipment, nil
+}
+
+func (u *equipmentUC) checkAccess(ctx context.Context, equipment *models.Equipment) error {
+	if equipment.TeamID == nil {
+		return nil
+	}
+
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	return u.canAccess(ctx, equipment, user)
+}
+
+// Team scoped equipment is only available to team members and org admins
+func (u *equipmentUC) canAccess(ctx context.Context, equipment *models.Equipment, user *models.User) error {
+	if equipment.TeamID == nil || user.IsAdmin() {
+		return nil
+	}
+
+	member, err := u.equipmentRepo.IsTeamMember(ctx, *equipment.TeamID, user.UserID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return httpErrors.NewForbiddenError("equipment belongs to another team")
+	}
+	return nil
+}
+
+// Scheduled job marking reservations whose equipment wasn't returned in time
+func (u *equipmentUC) ProcessOverdue(ctx context.Context) error {
+	overdue, err := u.equipmentRepo.MarkOverdue(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range overdue {
+		u.notify(ctx, models.NotificationReservationOverdue, &r.UsersEquipment, r.EquipmentName, "")
+	}
+	return nil
+}
+
+// Scheduled job reminding of reservations starting or ending within the reminder lead
+func (u *equipmentUC) SendReminders(ctx context.Context) error {
+	before := time.Now().Add(time.Minute * u.cfg.Scheduler.ReminderLead)
+
+	starting, err := u.equipmentRepo.ClaimStartReminders(ctx, before)
+	if err != nil {
+		return err
+	}
+	for _, r := range starting {
+		u.notify(ctx, models.NotificationReservationStarting, &r.UsersEquipment, r.EquipmentName, "")
+	}
+
+	ending, err := u.equipmentRepo.ClaimEndReminders(ctx, before)
+	if err != nil {
+		return err
+	}
+	for _, r := range ending {
+		u.notify(ctx, models.NotificationReservationEnding, &r.UsersEquipment, r.EquipmentName, "")
+	}
+	return nil
+}
+
+// Notify the reservation owner. Failures are only logged, the change the
+// notification is about is already done
+func (u *equipmentUC) notify(
+	ctx context.Context,
+	kind string,
+	reservation *models.UsersEquipment,
+	equipmentName string,
+	reason string,
+) {
+	reservationID := reservation.Id
+	if err := u.notifier.Notify(ctx, &models.Notification{
+		UserID:        reservation.UserID,
+		Kind:          kind,
+		ReservationID: &reservationID,
+		Params: models.NotificationParams{
+			EquipmentName:    equipmentName,
+			ReservationStart: reservation.ReservationStart,
+			ReservationEnd:   reservation.ReservationEnd,
+			Status:           reservation.Status,
+			Reason:           reason,
+		},
+	}); err != nil {
+		u.logger.Errorf("equipmentUC.notify: %v", err)
+	}
+}
+
+// Stores an uploaded photo or document. The type is sniffed from the content, photos
+// get a JPEG thumbnail when they can be decoded
+func (u *equipmentUC) UploadAttachment(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	fileName string,
+	file io.ReadSeeker,
+	size int64,
+) (*models.Attachment, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = u.getByID(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	if size > u.cfg.Storage.MaxUploadSize<<20 {
+		return nil, httpErrors.NewRestErrorWithMessage(http.StatusRequestEntityTooLarge, httpErrors.ErrFileTooLarge, nil)
+	}
+
+	head := make([]byte, media.SniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, errors.Wrap(err, "equipmentUC.UploadAttachment.ReadHead")
+	}
+	contentType, kind, ok := media.Sniff(head[:n])
+	if !ok {
+		return nil, httpErrors.NewRestError(http.StatusUnsupportedMediaType, httpErrors.ErrFileTypeNotAllowed,
+			httpErrors.NotAllowedImageHeader)
+	}
+
+	attachment := &models.Attachment{
+		AttachmentID: uuid.New(),
+		EquipmentID:  equipmentID,
+		Kind:         kind,
+		FileName:     fileName,
+		ContentType:  contentType,
+		Size:         size,
+		UploadedBy:   user.UserID,
+	}
+	attachment.StorageKey = fmt.Sprintf("equipment/%s/%s", equipmentID, attachment.AttachmentID)
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, "equipmentUC.UploadAttachment.Seek")
+	}
+	if err = u.storage.Put(ctx, attachment.StorageKey, file); err != nil {
+		return nil, err
+	}
+
+	if kind == models.AttachmentPhoto {
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return nil, errors.Wrap(err, "equipmentUC.UploadAttachment.Seek")
+		}
+		thumbnail, ok, err := media.Thumbnail(file, u.cfg.Storage.ThumbnailSize)
+		if err != nil {
+			u.logger.Errorf("equipmentUC.UploadAttachment.Thumbnail: %v", err)
+		}
+		if ok {
+			key := attachment.StorageKey + "_thumb.jpg"
+			if err := u.storage.Put(ctx, key, bytes.NewReader(thumbnail)); err != nil {
+				u.logger.Errorf("equipmentUC.UploadAttachment.PutThumbnail: %v", err)
+			} else {
+				attachment.ThumbnailKey = key
+			}
+		}
+	}
+
+	if err = u.equipmentRepo.CreateAttachment(ctx, attachment); err != nil {
+		u.removeAttachmentFiles(ctx, attachment)
+		return nil, err
+	}
+
+	u.setAttachmentURLs(attachment)
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityAttachment, attachment.AttachmentID.String(), nil, attachment)
+	return attachment, nil
+}
+
+// Content of the attachment or of its thumbnail
+func (u *equipmentUC) GetAttachmentContent(
+	ctx context.Context,
+	equipmentID uuid.UUID,
+	attachmentID uuid.UUID,
+	thumbnail bool,
+) (*models.Attachment, io.ReadCloser, error) {
+	if _, err := u.getByID(ctx, equipmentID); err != nil {
+		return nil, nil, err
+	}
+	attachment, err := u.equipmentRepo.GetAttachment(ctx, equipmentID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := attachment.StorageKey
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, nil, httpErrors.NewNotFoundError("attachment has no thumbnail")
+		}
+		key = attachment.ThumbnailKey
+		attachment.ContentType = "image/jpeg"
+	}
+	content, err := u.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, httpErrors.NewNotFoundError(err)
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+func (u *equipmentUC) DeleteAttachment(ctx context.Context, equipmentID uuid.UUID, attachmentID uuid.UUID) error {
+	attachment, err := u.equipmentRepo.GetAttachment(ctx, equipmentID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.DeleteAttachment(ctx, equipmentID, attachmentID); err != nil {
+		return err
+	}
+
+	u.removeAttachmentFiles(ctx, attachment)
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityAttachment, attachmentID.String(), attachment, nil)
+	return nil
+}
+
+func (u *equipmentUC) removeAttachmentFiles(ctx context.Context, attachment *models.Attachment) {
+	for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := u.storage.Delete(ctx, key); err != nil {
+			u.logger.Errorf("equipmentUC.removeAttachmentFiles: %v", err)
+		}
+	}
+}
+
+func (u *equipmentUC) setAttachmentURLs(attachment *models.Attachment) {
+	attachment.URL = fmt.Sprintf("%s/api/equipment/%s/attachments/%s",
+		u.cfg.Server.PublicURL, attachment.EquipmentID, attachment.AttachmentID)
+	if attachment.ThumbnailKey != "" {
+		attachment.ThumbnailURL = attachment.URL + "?thumbnail=true"
+	}
+}
+
+func (u *equipmentUC) CreateKit(ctx context.Context, kit *models.Kit) (*models.Kit, error) {
+	if err := u.checkKitItems(ctx, kit); err != nil {
+		return nil, err
+	}
+	if err := u.equipmentRepo.CreateKit(ctx, kit); err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityKit, kit.KitID.String(), nil, kit)
+	return kit, nil
+}
+
+func (u *equipmentUC) UpdateKit(ctx context.Context, kit *models.Kit) error {
+	before, err := u.equipmentRepo.GetKit(ctx, kit.KitID)
+	if err != nil {
+		return err
+	}
+	if err = u.checkKitItems(ctx, kit); err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.UpdateKit(ctx, kit); err != nil {
+		return err
+	}
+	kit.CreatedAt = before.CreatedAt
+	u.auditor.Record(ctx, models.AuditUpdate, models.AuditEntityKit, kit.KitID.String(), before, kit)
+	return nil
+}
+
+// Every component must exist and be listed once, with no more units than the pool holds
+func (u *equipmentUC) checkKitItems(ctx context.Context, kit *models.Kit) error {
+	seen := make(map[uuid.UUID]bool, len(kit.Items))
+	for i := range kit.Items {
+		item := &kit.Items[i]
+		if seen[item.EquipmentID] {
+			return httpErrors.NewBadRequestError("each equipment may be listed in a kit only once")
+		}
+		seen[item.EquipmentID] = true
+
+		equipment, err := u.equipmentRepo.GetByID(ctx, item.EquipmentID)
+		if err != nil {
+			return err
+		}
+		if item.Quantity, err = checkQuantity(equipment, item.Quantity); err != nil {
+			return err
+		}
+		item.EquipmentName = equipment.Name
+	}
+	return nil
+}
+
+func (u *equipmentUC) DeleteKit(ctx context.Context, kitID uuid.UUID) error {
+	before, err := u.equipmentRepo.GetKit(ctx, kitID)
+	if err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.DeleteKit(ctx, kitID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityKit, kitID.String(), before, nil)
+	return nil
+}
+
+func (u *equipmentUC) GetKit(ctx context.Context, kitID uuid.UUID) (*models.Kit, error) {
+	kit, err := u.equipmentRepo.GetKit(ctx, kitID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accessible, err := u.canAccessKit(ctx, kit, user, make(map[uuid.UUID]bool))
+	if err != nil {
+		return nil, err
+	}
+	if !accessible {
+		return nil, httpErrors.NewForbiddenError("kit holds equipment of another team")
+	}
+	return kit, nil
+}
+
+// Kits holding team scoped equipment are listed only to those who can access every component
+func (u *equipmentUC) GetKits(ctx context.Context) ([]models.Kit, error) {
+	kits, err := u.equipmentRepo.GetKits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin() {
+		return kits, nil
+	}
+
+	memberships := make(map[uuid.UUID]bool)
+	visible := make([]models.Kit, 0, len(kits))
+	for i := range kits {
+		accessible, err := u.canAccessKit(ctx, &kits[i], user, memberships)
+		if err != nil {
+			return nil, err
+		}
+		if accessible {
+			visible = append(visible, kits[i])
+		}
+	}
+	return visible, nil
+}
+
+// Reports whether the user can access every kit component. Team memberships looked up
+// are kept in memberships for the next kits
+func (u *equipmentUC) canAccessKit(
+	ctx context.Context,
+	kit *models.Kit,
+	user *models.User,
+	memberships map[uuid.UUID]bool,
+) (bool, error) {
+	if user.IsAdmin() {
+		return true, nil
+	}
+	for _, item := range kit.Items {
+		equipment, err := u.equipmentRepo.GetByID(ctx, item.EquipmentID)
+		if err != nil {
+			return false, err
+		}
+		if equipment.TeamID == nil {
+			continue
+		}
+		member, ok := memberships[*equipment.TeamID]
+		if !ok {
+			if member, err = u.equipmentRepo.IsTeamMember(ctx, *equipment.TeamID, user.UserID); err != nil {
+				return false, err
+			}
+			memberships[*equipment.TeamID] = member
+		}
+		if !member {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Books every kit component for the window in one transaction. Each component must be
+// reservable by the user on its own, when any of them collides nothing is booked and the
+// colliding components are returned
+func (u *equipmentUC) ReserveKit(
+	ctx context.Context,
+	kr *models.KitReservation,
+) (*models.KitReservation, []models.UsersEquipment, error) {
+	if !kr.ReservationEnd.After(kr.ReservationStart) {
+		return nil, nil, httpErrors.NewBadRequestError("reservation end must be after its start")
+	}
+	kit, err := u.equipmentRepo.GetKit(ctx, kr.KitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	kr.UserID = user.UserID
+
+	equipments := make(map[uuid.UUID]*models.Equipment, len(kit.Items))
+	reservations := make([]models.UsersEquipment, 0, len(kit.Items))
+	// components accepted so far count against the limits of the next ones
+	pending := make([]policy.Pending, 0, len(kit.Items))
+	for _, item := range kit.Items {
+		equipment, err := u.getByID(ctx, item.EquipmentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = checkReservable(equipment); err != nil {
+			return nil, nil, err
+		}
+		reservation := models.UsersEquipment{
+			UserID:           user.UserID,
+			EquipmentID:      item.EquipmentID,
+			ReservationStart: kr.ReservationStart,
+			ReservationEnd:   kr.ReservationEnd,
+			Status:           models.ReservationConfirmed,
+		}
+		if reservation.Quantity, err = checkQuantity(equipment, item.Quantity); err != nil {
+			return nil, nil, err
+		}
+		if equipment.RequiresApproval && !equipment.CanApprove(user) {
+			reservation.Status = models.ReservationPending
+		}
+		if err = u.checkPolicies(ctx, user, equipment, &reservation, nil, pending); err != nil {
+			return nil, nil, err
+		}
+		equipments[item.EquipmentID] = equipment
+		reservations = append(reservations, reservation)
+		pending = append(pending, policy.Pending{Reservation: reservation, EquipmentType: equipment.Type})
+	}
+
+	conflicts, err := u.equipmentRepo.ReserveKit(ctx, kr, reservations)
+	if err != nil || len(conflicts) > 0 {
+		return nil, conflicts, err
+	}
+	u.auditor.Record(ctx, models.AuditReserve, models.AuditEntityKitReservation, kr.KitReservationID.String(), nil, kr)
+	for i := range kr.Reservations {
+		reservation := &kr.Reservations[i]
+		u.notify(ctx, models.NotificationReservationCreated, reservation, equipments[reservation.EquipmentID].Name, "")
+		u.publisher.Publish(ctx, models.EventReservationCreated, reservation)
+	}
+	return kr, nil, nil
+}
+
+func (u *equipmentUC) GetKitReservation(ctx context.Context, kitReservationID uuid.UUID) (*models.KitReservation, error) {
+	return u.getKitReservationForOwner(ctx, kitReservationID)
+}
+
+// Cancels every component reservation which hasn't ended yet
+func (u *equipmentUC) CancelKitReservation(ctx context.Context, kitReservationID uuid.UUID) error {
+	kr, err := u.getKitReservationForOwner(ctx, kitReservationID)
+	if err != nil {
+		return err
+	}
+
+	cancelled, err := u.equipmentRepo.CancelKitReservation(ctx, kitReservationID)
+	if err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditCancel, models.AuditEntityKitReservation, kitReservationID.String(), kr,
+		map[string]interface{}{"cancelled": cancelled})
+	for i := range cancelled {
+		ue := &cancelled[i]
+		if equipment, err := u.equipmentRepo.GetByID(ctx, ue.EquipmentID); err == nil {
+			u.notify(ctx, models.NotificationReservationCancelled, ue, equipment.Name, "")
+		}
+		u.publisher.Publish(ctx, models.EventReservationCancelled, ue)
+		u.processWaitlist(ctx, ue.EquipmentID, ue.ReservationStart, ue.ReservationEnd)
+	}
+	return nil
+}
+
+func (u *equipmentUC) getKitReservationForOwner(
+	ctx context.Context,
+	kitReservationID uuid.UUID,
+) (*models.KitReservation, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kr, err := u.equipmentRepo.GetKitReservation(ctx, kitReservationID)
+	if err != nil {
+		return nil, err
+	}
+	if kr.UserID != user.UserID && !user.IsAdmin() {
+		return nil, httpErrors.NewForbiddenError("only the owner and admins may manage the kit reservation")
+	}
+	return kr, nil
+}
+
+// Defines or replaces the attribute schema of the type. A schema which the attributes of
+// equipment in the catalog already break is refused, the offending equipment is listed
+func (u *equipmentUC) SetEquipmentType(ctx context.Context, equipmentType *models.EquipmentType) error {
+	if len(equipmentType.Type) > 50 {
+		return httpErrors.NewBadRequestError("type must be at most 50 characters long")
+	}
+	schema, err := attributes.Parse(equipmentType.Schema)
+	if err != nil {
+		return httpErrors.NewBadRequestError(err.Error())
+	}
+	before, err := u.equipmentRepo.GetEquipmentType(ctx, equipmentType.Type)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	existing, err := u.equipmentRepo.GetTypeAttributes(ctx, equipmentType.Type)
+	if err != nil {
+		return err
+	}
+	invalid := make(map[string]string)
+	for equipmentID, attrs := range existing {
+		if err := schema.Validate(attrs); err != nil {
+			invalid[equipmentID.String()] = err.Error()
+		}
+	}
+	if len(invalid) > 0 {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrSchemaBreaksEquipment, invalid)
+	}
+
+	if err = u.equipmentRepo.SetEquipmentType(ctx, equipmentType); err != nil {
+		return err
+	}
+	action := models.AuditUpdate
+	if before == nil {
+		action = models.AuditCreate
+	}
+	u.auditor.Record(ctx, action, models.AuditEntityEquipmentType, equipmentType.Type, before, equipmentType)
+	return nil
+}
+
+// Equipment of the type keeps its attributes, they are just no longer validated
+func (u *equipmentUC) DeleteEquipmentType(ctx context.Context, equipmentType string) error {
+	before, err := u.equipmentRepo.GetEquipmentType(ctx, equipmentType)
+	if err != nil {
+		return err
+	}
+	if err = u.equipmentRepo.DeleteEquipmentType(ctx, equipmentType); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityEquipmentType, equipmentType, before, nil)
+	return nil
+}
+
+func (u *equipmentUC) GetEquipmentType(ctx context.Context, equipmentType string) (*models.EquipmentType, error) {
+	return u.equipmentRepo.GetEquipmentType(ctx, equipmentType)
+}
+
+func (u *equipmentUC) GetEquipmentTypes(ctx context.Context) ([]models.EquipmentType, error) {
+	return u.equipmentRepo.GetEquipmentTypes(ctx)
 }
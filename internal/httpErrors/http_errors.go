@@ -20,6 +20,27 @@ const (
 	ErrUnauthorized       = "Unauthorized"
 	ErrForbidden          = "Forbidden"
 	ErrBadQueryParams     = "Invalid query params"
+	ErrEmailRequired      = "Email is required"
+	ErrEmailNotAllowed    = "Email domain is not allowed"
+	ErrEmailVerified      = "Email is already verified"
+
+	ErrReservationNotPending = "Reservation is not pending"
+	ErrReservationNotActive  = "Reservation is not active"
+	ErrEquipmentAvailable    = "Equipment is available in the requested window"
+	ErrWaitlistNotWaiting    = "Waitlist entry is not waiting"
+	ErrReservationConflict   = "Reservations collide with existing ones"
+	ErrEquipmentCheckedOut   = "Equipment is checked out"
+	ErrEquipmentNotOut       = "Equipment is not checked out on this reservation"
+	ErrEquipmentArchived     = "Equipment is archived"
+	ErrEquipmentNotArchived  = "Equipment is not archived"
+	ErrEquipmentHasUpcoming  = "Equipment has reservations which haven't ended yet"
+	ErrEquipmentOutOfService = "Equipment is out of service"
+	ErrPoolFull              = "Every unit of the pool is registered already, raise its quantity first"
+	ErrAssetIdentifierTaken  = "Serial number or asset tag is already in use"
+	ErrFileTooLarge          = "File is too large"
+	ErrFileTypeNotAllowed    = "Only JPEG, PNG, GIF and WebP images and PDF documents may be uploaded"
+	ErrLocationNotEmpty      = "Location still holds other locations or equipment"
+	ErrSchemaBreaksEquipment = "Attributes of equipment of the type don't match the schema"
 )
 
 var (
@@ -39,6 +60,8 @@ var (
 	ExistsEmailError      = errors.New("user with given email already exists")
 	InvalidJWTToken       = errors.New("invalid JWT token")
 	InvalidJWTClaims      = errors.New("invalid JWT claims")
+	InvalidAPIKey         = errors.New("invalid API key")
+	InvalidFeedToken      = errors.New("invalid calendar feed token")
 	NotAllowedImageHeader = errors.New("not allowed image header")
 	NoCookie              = errors.New("not found cookie header")
 )
@@ -159,6 +182,8 @@ func ParseErrors(err error) RestErr {
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return NewRestError(http.StatusNotFound, NotFound.Error(), err)
+	case errors.Is(err, InvalidAPIKey) || errors.Is(err, InvalidFeedToken):
+		return NewRestError(http.StatusUnauthorized, Unauthorized.Error(), err)
 	case errors.Is(err, Forbidden):
 		return NewRestError(http.StatusForbidden, Forbidden.Error(), err)
 	case errors.Is(err, BadQueryParams):
@@ -187,6 +212,11 @@ func ParseErrors(err error) RestErr {
 	}
 }
 
+// Reports whether err is a unique constraint violation
+func IsUniqueViolation(err error) bool {
+	return strings.Contains(err.Error(), "SQLSTATE 23505")
+}
+
 func parseSqlErrors(err error) RestErr {
 	if strings.Contains(err.Error(), "23505") {
 		return NewRestError(http.StatusBadRequest, ExistsEmailError.Error(), err)
@@ -0,0 +1,19 @@
+package location
+
+import (
+	"context"
+	"equiptrack/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, location *models.Location) (*models.Location, error)
+	Update(ctx context.Context, location *models.Location) error
+	Delete(ctx context.Context, locationID uuid.UUID) (bool, error)
+	GetByID(ctx context.Context, locationID uuid.UUID) (*models.Location, error)
+	GetLocations(ctx context.Context) ([]models.Location, error)
+	GetChildren(ctx context.Context, parentID uuid.UUID) ([]models.Location, error)
+	GetPath(ctx context.Context, locationID uuid.UUID) ([]models.Location, error)
+	IsInSubtree(ctx context.Context, locationID uuid.UUID, rootID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,11 @@
+package location
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	GetTree() echo.HandlerFunc
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"equiptrack/config"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/location"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type locationHandlers struct {
+	cfg        *config.Config
+	locationUC location.UseCase
+	logger     *logrus.Logger
+}
+
+// NewLocationHandlers Location handlers constructor
+func NewLocationHandlers(cfg *config.Config, locationUC location.UseCase, log *logrus.Logger) location.Handlers {
+	return &locationHandlers{cfg: cfg, locationUC: locationUC, logger: log}
+}
+
+func (h *locationHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		l := &models.Location{}
+		if err := utils.ReadRequest(c, l); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		createdLocation, err := h.locationUC.Create(c.Request().Context(), l)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusCreated, createdLocation)
+	}
+}
+
+func (h *locationHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		lID, err := uuid.Parse(c.Param("location_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		l := &models.Location{}
+		if err := utils.ReadRequest(c, l); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+		l.LocationID = lID
+
+		if err = h.locationUC.Update(c.Request().Context(), l); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *locationHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		lID, err := uuid.Parse(c.Param("location_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.locationUC.Delete(c.Request().Context(), lID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *locationHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		lID, err := uuid.Parse(c.Param("location_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		l, err := h.locationUC.GetByID(c.Request().Context(), lID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, l)
+	}
+}
+
+func (h *locationHandlers) GetTree() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tree, err := h.locationUC.GetTree(c.Request().Context())
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, tree)
+	}
+}
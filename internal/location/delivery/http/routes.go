@@ -0,0 +1,21 @@
+package http
+
+import (
+	"equiptrack/internal/location"
+	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MapLocationRoutes(locationGroup *echo.Group, h location.Handlers, mw *middleware.MiddlewareManager) {
+	read := mw.RequireScope(models.ScopeEquipmentRead)
+	write := mw.RequireScope(models.ScopeEquipmentWrite)
+
+	locationGroup.Use(mw.AuthJWTMiddleware)
+	locationGroup.POST("", h.Create(), mw.IsAdminMiddleware, write)
+	locationGroup.GET("", h.GetTree(), read)
+	locationGroup.GET("/:location_id", h.GetByID(), read)
+	locationGroup.PUT("/:location_id", h.Update(), mw.IsAdminMiddleware, write)
+	locationGroup.DELETE("/:location_id", h.Delete(), mw.IsAdminMiddleware, write)
+}
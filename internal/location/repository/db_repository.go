@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/internal/location"
+	"equiptrack/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type locationRepo struct {
+	db *sql.DB
+}
+
+// Location Repository constructor
+func NewLocationRepository(db *sql.DB) location.Repository {
+	return &locationRepo{db: db}
+}
+
+func (r *locationRepo) Create(ctx context.Context, location *models.Location) (*models.Location, error) {
+	l := &models.Location{}
+	if err := r.db.QueryRowContext(
+		ctx, qCreateLocation, location.ParentID, location.Kind, location.Name, location.Description,
+	).Scan(&l.LocationID, &l.ParentID, &l.Kind, &l.Name, &l.Description, &l.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "locationRepo.Create.QueryRowContext")
+	}
+	return l, nil
+}
+
+func (r *locationRepo) Update(ctx context.Context, location *models.Location) error {
+	result, err := r.db.ExecContext(
+		ctx, qUpdateLocation, location.ParentID, location.Kind, location.Name, location.Description, location.LocationID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "locationRepo.Update.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "locationRepo.Update.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "locationRepo.Update.rowsAffected")
+	}
+	return nil
+}
+
+// Deletes the location unless other locations or equipment are in it, false is returned then
+func (r *locationRepo) Delete(ctx context.Context, locationID uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx, qDeleteLocation, locationID)
+	if err != nil {
+		return false, errors.Wrap(err, "locationRepo.Delete.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "locationRepo.Delete.RowsAffected")
+	}
+	return rowsAffected != 0, nil
+}
+
+func (r *locationRepo) GetByID(ctx context.Context, locationID uuid.UUID) (*models.Location, error) {
+	l := &models.Location{}
+	if err := r.db.QueryRowContext(ctx, qGetLocation, locationID).Scan(
+		&l.LocationID, &l.ParentID, &l.Kind, &l.Name, &l.Description, &l.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "locationRepo.GetByID.QueryRowContext")
+	}
+	return l, nil
+}
+
+func (r *locationRepo) GetLocations(ctx context.Context) ([]models.Location, error) {
+	rows, err := r.db.QueryContext(ctx, qGetLocations)
+	if err != nil {
+		return nil, errors.Wrap(err, "locationRepo.GetLocations.QueryContext")
+	}
+	defer rows.Close()
+
+	locations, err := scanLocations(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "locationRepo.GetLocations.ScanRows")
+	}
+	return locations, nil
+}
+
+// Locations nested directly in the parent
+func (r *locationRepo) GetChildren(ctx context.Context, parentID uuid.UUID) ([]models.Location, error) {
+	rows, err := r.db.QueryContext(ctx, qGetChildLocations, parentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "locationRepo.GetChildren.QueryContext")
+	}
+	defer rows.Close()
+
+	children, err := scanLocations(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "locationRepo.GetChildren.ScanRows")
+	}
+	return children, nil
+}
+
+// Ancestors of the location, the site first
+func (r *locationRepo) GetPath(ctx context.Context, locationID uuid.UUID) ([]models.Location, error) {
+	rows, err := r.db.QueryContext(ctx, qGetLocationPath, locationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "locationRepo.GetPath.QueryContext")
+	}
+	defer rows.Close()
+
+	path, err := scanLocations(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "locationRepo.GetPath.ScanRows")
+	}
+	return path, nil
+}
+
+// Reports whether the location is the root or nested anywhere below it
+func (r *locationRepo) IsInSubtree(ctx context.Context, locationID uuid.UUID, rootID uuid.UUID) (bool, error) {
+	var inSubtree bool
+	if err := r.db.QueryRowContext(ctx, qIsInSubtree, locationID, rootID).Scan(&inSubtree); err != nil {
+		return false, errors.Wrap(err, "locationRepo.IsInSubtree.QueryRowContext")
+	}
+	return inSubtree, nil
+}
+
+func scanLocations(rows *sql.Rows) ([]models.Location, error) {
+	var locations = make([]models.Location, 0)
+	for rows.Next() {
+		var l models.Location
+		if err := rows.Scan(&l.LocationID, &l.ParentID, &l.Kind, &l.Name, &l.Description, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		locations = append(locations, l)
+	}
+	return locations, rows.Err()
+}
@@ -0,0 +1,42 @@
+package repository
+
+const (
+	qCreateLocation = `INSERT INTO locations (parent_id, kind, name, description) VALUES ($1, $2, $3, $4)
+	RETURNING location_id, parent_id, kind, name, description, created_at`
+	qUpdateLocation = `UPDATE locations SET parent_id = $1, kind = $2, name = $3, description = $4 WHERE location_id = $5`
+	// locations still holding other locations or equipment are kept
+	qDeleteLocation = `DELETE FROM locations
+	WHERE location_id = $1
+	AND NOT EXISTS (SELECT 1 FROM locations WHERE parent_id = $1)
+	AND NOT EXISTS (SELECT 1 FROM equipment WHERE location_id = $1)`
+	qGetLocation = `SELECT location_id, parent_id, kind, name, description, created_at
+	FROM locations
+	WHERE location_id = $1`
+	qGetLocations = `SELECT location_id, parent_id, kind, name, description, created_at
+	FROM locations
+	ORDER BY name`
+	qGetChildLocations = `SELECT location_id, parent_id, kind, name, description, created_at
+	FROM locations
+	WHERE parent_id = $1
+	ORDER BY name`
+
+	// ancestors of the location starting at the top
+	qGetLocationPath = `WITH RECURSIVE path AS (
+		SELECT location_id, parent_id, kind, name, description, created_at, 0 AS depth
+		FROM locations
+		WHERE location_id = (SELECT parent_id FROM locations WHERE location_id = $1)
+		UNION ALL
+		SELECT l.location_id, l.parent_id, l.kind, l.name, l.description, l.created_at, p.depth + 1
+		FROM locations l
+		INNER JOIN path p ON l.location_id = p.parent_id
+	)
+	SELECT location_id, parent_id, kind, name, description, created_at
+	FROM path
+	ORDER BY depth DESC`
+	qIsInSubtree = `WITH RECURSIVE subtree AS (
+		SELECT location_id FROM locations WHERE location_id = $2
+		UNION ALL
+		SELECT l.location_id FROM locations l INNER JOIN subtree s ON l.parent_id = s.location_id
+	)
+	SELECT EXISTS (SELECT 1 FROM subtree WHERE location_id = $1)`
+)
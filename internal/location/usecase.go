@@ -0,0 +1,16 @@
+package location
+
+import (
+	"context"
+	"equiptrack/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type UseCase interface {
+	Create(ctx context.Context, location *models.Location) (*models.Location, error)
+	Update(ctx context.Context, location *models.Location) error
+	Delete(ctx context.Context, locationID uuid.UUID) error
+	GetByID(ctx context.Context, locationID uuid.UUID) (*models.Location, error)
+	GetTree(ctx context.Context) ([]models.LocationNode, error)
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/config"
+	"equiptrack/internal/audit"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/location"
+	"equiptrack/internal/models"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type locationUC struct {
+	cfg          *config.Config
+	locationRepo location.Repository
+	auditor      audit.Recorder
+	logger       *logrus.Logger
+}
+
+func NewLocationUseCase(
+	cfg *config.Config,
+	locationRepo location.Repository,
+	auditor audit.Recorder,
+	log *logrus.Logger,
+) location.UseCase {
+	return &locationUC{cfg: cfg, locationRepo: locationRepo, auditor: auditor, logger: log}
+}
+
+func (u *locationUC) Create(ctx context.Context, location *models.Location) (*models.Location, error) {
+	if err := u.checkParent(ctx, location); err != nil {
+		return nil, err
+	}
+	created, err := u.locationRepo.Create(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityLocation, created.LocationID.String(), nil, created)
+	return created, nil
+}
+
+func (u *locationUC) Update(ctx context.Context, location *models.Location) error {
+	before, err := u.locationRepo.GetByID(ctx, location.LocationID)
+	if err != nil {
+		return err
+	}
+	if err = u.checkParent(ctx, location); err != nil {
+		return err
+	}
+	if location.ParentID != nil {
+		// moving a location below itself would detach the subtree from the sites
+		cycle, err := u.locationRepo.IsInSubtree(ctx, *location.ParentID, location.LocationID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return httpErrors.NewBadRequestError("location can't be nested in itself")
+		}
+	}
+	if location.Kind != before.Kind {
+		if err = u.checkChildren(ctx, location); err != nil {
+			return err
+		}
+	}
+	if err = u.locationRepo.Update(ctx, location); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditUpdate, models.AuditEntityLocation, location.LocationID.String(), before, location)
+	return nil
+}
+
+// Only empty locations are deleted, equipment and nested locations have to be moved first
+func (u *locationUC) Delete(ctx context.Context, locationID uuid.UUID) error {
+	before, err := u.locationRepo.GetByID(ctx, locationID)
+	if err != nil {
+		return err
+	}
+	deleted, err := u.locationRepo.Delete(ctx, locationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.ErrLocationNotEmpty, nil)
+	}
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityLocation, locationID.String(), before, nil)
+	return nil
+}
+
+func (u *locationUC) GetByID(ctx context.Context, locationID uuid.UUID) (*models.Location, error) {
+	location, err := u.locationRepo.GetByID(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location.Path, err = u.locationRepo.GetPath(ctx, locationID); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// All locations nested under their parents, sites at the top
+func (u *locationUC) GetTree(ctx context.Context) ([]models.LocationNode, error) {
+	locations, err := u.locationRepo.GetLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uuid.UUID][]models.Location)
+	roots := make([]models.Location, 0)
+	for _, l := range locations {
+		if l.ParentID == nil {
+			roots = append(roots, l)
+		} else {
+			children[*l.ParentID] = append(children[*l.ParentID], l)
+		}
+	}
+	return buildTree(roots, children), nil
+}
+
+func buildTree(locations []models.Location, children map[uuid.UUID][]models.Location) []models.LocationNode {
+	nodes := make([]models.LocationNode, 0, len(locations))
+	for _, l := range locations {
+		nodes = append(nodes, models.LocationNode{
+			Location: l,
+			Children: buildTree(children[l.LocationID], children),
+		})
+	}
+	return nodes
+}
+
+// Sites are top level, every other kind is nested in a shallower one
+func (u *locationUC) checkParent(ctx context.Context, location *models.Location) error {
+	parentKind := ""
+	if location.ParentID != nil {
+		parent, err := u.locationRepo.GetByID(ctx, *location.ParentID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return httpErrors.NewBadRequestError("parent location not found")
+			}
+			return err
+		}
+		parentKind = parent.Kind
+	}
+	if !models.LocationKindAllowed(location.Kind, parentKind) {
+		return httpErrors.NewBadRequestError("a " + location.Kind + " can't be placed there, " +
+			"locations nest as site, building, room, shelf")
+	}
+	return nil
+}
+
+// Locations nested in a location which changes its kind must still fit in it
+func (u *locationUC) checkChildren(ctx context.Context, location *models.Location) error {
+	children, err := u.locationRepo.GetChildren(ctx, location.LocationID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if !models.LocationKindAllowed(child.Kind, location.Kind) {
+			return httpErrors.NewBadRequestError("a " + location.Kind + " can't hold the " + child.Kind + " " +
+				child.Name + ", locations nest as site, building, room, shelf")
+		}
+	}
+	return nil
+}
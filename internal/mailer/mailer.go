@@ -0,0 +1,123 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"equiptrack/config"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	smtpDriver = "smtp"
+
+	defaultSMTPTimeout = 10
+)
+
+// Header values come from user input like equipment names, line breaks would start new headers
+var headerEscaper = strings.NewReplacer("\r", " ", "\n", " ")
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Mailer constructor, anything but smtp driver falls back to logging messages
+func NewMailer(cfg *config.Config, logger *logrus.Logger) Mailer {
+	if cfg.Mail.Driver == smtpDriver {
+		return &smtpMailer{cfg: &cfg.Mail}
+	}
+	return &logMailer{logger: logger}
+}
+
+type smtpMailer struct {
+	cfg *config.MailConfig
+}
+
+// Same exchange as smtp.SendMail, bounded by the configured timeout and the context
+func (m *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	timeout := m.cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultSMTPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second*timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.cfg.Host, m.cfg.Port))
+	if err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.Dial")
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err = conn.SetDeadline(deadline); err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.SetDeadline")
+	}
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.NewClient")
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return errors.Wrap(err, "smtpMailer.Send.StartTLS")
+		}
+	}
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err = c.Auth(auth); err != nil {
+			return errors.Wrap(err, "smtpMailer.Send.Auth")
+		}
+	}
+	if err = c.Mail(m.cfg.From); err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.Mail")
+	}
+	if err = c.Rcpt(msg.To); err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.Rcpt")
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.Data")
+	}
+	body := strings.Join([]string{
+		"From: " + headerEscaper.Replace(m.cfg.From),
+		"To: " + headerEscaper.Replace(msg.To),
+		"Subject: " + headerEscaper.Replace(msg.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+	if _, err = w.Write([]byte(body)); err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.Write")
+	}
+	if err = w.Close(); err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.Close")
+	}
+	if err = c.Quit(); err != nil {
+		return errors.Wrap(err, "smtpMailer.Send.Quit")
+	}
+	return nil
+}
+
+// Local stand-in for development
+type logMailer struct {
+	logger *logrus.Logger
+}
+
+func (m *logMailer) Send(ctx context.Context, msg *Message) error {
+	m.logger.Infof("Mail to: %s, Subject: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -15,8 +15,18 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const apiKeyHeader = "X-API-Key"
+
 func (mw *MiddlewareManager) AuthJWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		// machine integrations authenticate with an API key instead of the JWT
+		if apiKey := c.Request().Header.Get(apiKeyHeader); apiKey != "" {
+			if err := mw.validateAPIKey(apiKey, c); err != nil {
+				return utils.ErrResponseWithLog(c, mw.logger, httpErrors.InvalidAPIKey)
+			}
+			return next(c)
+		}
+
 		tokenString := c.Request().Header.Get("Authorization")
 
 		if tokenString != "" {
@@ -77,3 +87,34 @@ func (mw *MiddlewareManager) validateJWTToken(tokenString string, authUC auth.Us
 	}
 	return nil
 }
+
+func (mw *MiddlewareManager) validateAPIKey(apiKey string, c echo.Context) error {
+	key, err := mw.apiKeyUC.Authenticate(c.Request().Context(), apiKey)
+	if err != nil {
+		return err
+	}
+
+	u, err := mw.authUC.GetByID(c.Request().Context(), key.UserID)
+	if err != nil {
+		return err
+	}
+
+	c.Set("user", u)
+
+	ctx := context.WithValue(c.Request().Context(), utils.UserCtxKey{}, u)
+	ctx = context.WithValue(ctx, utils.APIKeyCtxKey{}, key)
+	c.SetRequest(c.Request().WithContext(ctx))
+	return nil
+}
+
+// Requests authenticated with an API key must have the given scope, JWT users are not restricted
+func (mw *MiddlewareManager) RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if key, ok := utils.GetAPIKeyFromCtx(c.Request().Context()); ok && !key.HasScope(scope) {
+				return utils.ErrResponseWithLog(c, mw.logger, httpErrors.Forbidden)
+			}
+			return next(c)
+		}
+	}
+}
@@ -11,7 +11,7 @@ func (mw *MiddlewareManager) IsAdminMiddleware(next echo.HandlerFunc) echo.Handl
 	return func(c echo.Context) error {
 		u, err := utils.GetUserFromCtx(c.Request().Context())
 
-		if err != nil || u.Role != "admin" {
+		if err != nil || !u.IsAdmin() {
 			return utils.ErrResponseWithLog(c, mw.logger, httpErrors.Forbidden)
 		}
 		return next(c)
@@ -2,19 +2,21 @@ package middleware
 
 import (
 	"equiptrack/config"
+	"equiptrack/internal/apikey"
 	"equiptrack/internal/auth"
 
 	"github.com/sirupsen/logrus"
 )
 
 type MiddlewareManager struct {
-	authUC  auth.UseCase
-	cfg     *config.Config
-	origins []string
-	logger  *logrus.Logger
+	authUC   auth.UseCase
+	apiKeyUC apikey.UseCase
+	cfg      *config.Config
+	origins  []string
+	logger   *logrus.Logger
 }
 
 // Middleware manager constructor
-func NewMiddlewareManager(authUC auth.UseCase, cfg *config.Config, origins []string, logger *logrus.Logger) *MiddlewareManager {
-	return &MiddlewareManager{authUC: authUC, cfg: cfg, origins: origins, logger: logger}
+func NewMiddlewareManager(authUC auth.UseCase, apiKeyUC apikey.UseCase, cfg *config.Config, origins []string, logger *logrus.Logger) *MiddlewareManager {
+	return &MiddlewareManager{authUC: authUC, apiKeyUC: apiKeyUC, cfg: cfg, origins: origins, logger: logger}
 }
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"context"
+	"equiptrack/internal/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Makes request ID and client address available to the use cases, must run after RequestID
+func (mw *MiddlewareManager) RequestMetaMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := context.WithValue(c.Request().Context(), utils.RequestMetaCtxKey{}, utils.RequestMeta{
+			RequestID: utils.GetRequestID(c),
+			IPAddress: utils.GetIPAddress(c),
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
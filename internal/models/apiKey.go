@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScopeEquipmentRead     = "equipment:read"
+	ScopeEquipmentWrite    = "equipment:write"
+	ScopeReservationsWrite = "reservations:write"
+	ScopeUsersRead         = "users:read"
+	ScopeUsersWrite        = "users:write"
+)
+
+var APIKeyScopes = []string{
+	ScopeEquipmentRead,
+	ScopeEquipmentWrite,
+	ScopeReservationsWrite,
+	ScopeUsersRead,
+	ScopeUsersWrite,
+}
+
+type ServiceAccount struct {
+	UserID      uuid.UUID `json:"user_id" db:"user_id" validate:"omitempty"`
+	Login       string    `json:"login" db:"login" validate:"required,lte=50"`
+	Role        string    `json:"role,omitempty" db:"role" validate:"omitempty,lte=20"`
+	Description string    `json:"description" db:"description" validate:"lte=200"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type ServiceAccountList struct {
+	TotalCount      int              `json:"total_count"`
+	TotalPages      int              `json:"total_pages"`
+	Page            int              `json:"page"`
+	Size            int              `json:"size"`
+	HasMore         bool             `json:"has_more"`
+	ServiceAccounts []ServiceAccount `json:"service_accounts"`
+}
+
+type APIKey struct {
+	KeyID      uuid.UUID  `json:"key_id" db:"key_id" validate:"omitempty"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name" validate:"required,lte=100"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     []string   `json:"scopes" db:"scopes" validate:"required,min=1"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Plain key is only returned once, right after creation
+type APIKeyWithSecret struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *APIKey) IsActive() bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	if k.ExpiresAt != nil && k.ExpiresAt.Before(time.Now()) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AttachmentPhoto    = "photo"
+	AttachmentDocument = "document"
+)
+
+// Photo or document of equipment, the content lives in storage under StorageKey
+type Attachment struct {
+	AttachmentID uuid.UUID `json:"attachment_id" db:"attachment_id"`
+	EquipmentID  uuid.UUID `json:"equipment_id" db:"equipment_id"`
+	Kind         string    `json:"kind" db:"kind"`
+	FileName     string    `json:"file_name" db:"file_name"`
+	ContentType  string    `json:"content_type" db:"content_type"`
+	Size         int64     `json:"size" db:"size"`
+	StorageKey   string    `json:"-" db:"storage_key"`
+	ThumbnailKey string    `json:"-" db:"thumbnail_key"`
+	UploadedBy   uuid.UUID `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditCreate        = "create"
+	AuditUpdate        = "update"
+	AuditDelete        = "delete"
+	AuditReserve       = "reserve"
+	AuditApprove       = "approve"
+	AuditReject        = "reject"
+	AuditCancel        = "cancel"
+	AuditShorten       = "shorten"
+	AuditReschedule    = "reschedule"
+	AuditCheckOut      = "check_out"
+	AuditCheckIn       = "check_in"
+	AuditJoinWaitlist  = "join_waitlist"
+	AuditLeaveWaitlist = "leave_waitlist"
+	AuditSetMember     = "set_member"
+	AuditDeleteMember  = "delete_member"
+	AuditRevoke        = "revoke"
+	AuditArchive       = "archive"
+	AuditRestore       = "restore"
+)
+
+const (
+	AuditEntityEquipment      = "equipment"
+	AuditEntityReservation    = "reservation"
+	AuditEntitySeries         = "reservation_series"
+	AuditEntityWaitlist       = "waitlist"
+	AuditEntityUser           = "user"
+	AuditEntityTeam           = "team"
+	AuditEntityServiceAccount = "service_account"
+	AuditEntityAPIKey         = "api_key"
+	AuditEntityWebhook        = "webhook"
+	AuditEntityCalendarFeed   = "calendar_feed"
+	AuditEntityAttachment     = "attachment"
+	AuditEntityLocation       = "location"
+	AuditEntityMaintenance    = "maintenance"
+	AuditEntityUnit           = "equipment_unit"
+	AuditEntityKit            = "kit"
+	AuditEntityKitReservation = "kit_reservation"
+	AuditEntityEquipmentType  = "equipment_type"
+)
+
+// Append-only record of a mutating operation. Changes maps every changed field
+// to its value before and after the operation
+type AuditEntry struct {
+	AuditID    int64           `json:"audit_id" db:"audit_id"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty" db:"actor_id"`
+	Action     string          `json:"action" db:"action"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   string          `json:"entity_id" db:"entity_id"`
+	Changes    json.RawMessage `json:"changes,omitempty" db:"changes"`
+	RequestID  string          `json:"request_id,omitempty" db:"request_id"`
+	IPAddress  string          `json:"ip_address,omitempty" db:"ip_address"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Empty fields do not restrict the query
+type AuditFilter struct {
+	ActorID    *uuid.UUID
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditList struct {
+	TotalCount int          `json:"total_count"`
+	TotalPages int          `json:"total_pages"`
+	Page       int          `json:"page"`
+	Size       int          `json:"size"`
+	HasMore    bool         `json:"has_more"`
+	Entries    []AuditEntry `json:"entries"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Secret iCalendar feed of user's reservations, or of all reservations of one
+// equipment when EquipmentID is set
+type CalendarFeed struct {
+	FeedID      uuid.UUID  `json:"feed_id" db:"feed_id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	EquipmentID *uuid.UUID `json:"equipment_id,omitempty" db:"equipment_id"`
+	Name        string     `json:"name" db:"name" validate:"lte=100"`
+	TokenHash   string     `json:"-" db:"token_hash"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Feed returned once on creation, the token can't be recovered later
+type CalendarFeedWithToken struct {
+	*CalendarFeed
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+type CalendarEvent struct {
+	ReservationID    int       `json:"reservation_id"`
+	EquipmentID      uuid.UUID `json:"equipment_id"`
+	EquipmentName    string    `json:"equipment_name"`
+	UserLogin        string    `json:"user_login"`
+	ReservationStart time.Time `json:"reservation_start"`
+	ReservationEnd   time.Time `json:"reservation_end"`
+	Status           string    `json:"status"`
+}
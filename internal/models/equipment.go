@@ -1,18 +1,108 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	EquipmentActive   = "active"
+	EquipmentInRepair = "in_repair"
+	EquipmentRetired  = "retired"
+	EquipmentLost     = "lost"
+
+	AttributeEqual          = "="
+	AttributeNotEqual       = "!="
+	AttributeGreater        = ">"
+	AttributeGreaterOrEqual = ">="
+	AttributeLess           = "<"
+	AttributeLessOrEqual    = "<="
+)
+
 type Equipment struct {
-	EquipmentID      uuid.UUID `json:"equipment_id" db:"equipment_id" validate:"omitempty"`
-	Name             string    `json:"name,omitempty" db:"name" validate:"omitempty,lte=100"`
-	ShortDescription string    `json:"short_description" db:"short_description" validate:"required,lte=200"`
-	FullDescription  string    `json:"full_description" db:"full_description"`
-	Reserved         bool      `json:"reserved" db:"reserved"`
-	// add type
+	EquipmentID      uuid.UUID  `json:"equipment_id" db:"equipment_id" validate:"omitempty"`
+	Name             string     `json:"name,omitempty" db:"name" validate:"omitempty,lte=100"`
+	ShortDescription string     `json:"short_description" db:"short_description" validate:"required,lte=200"`
+	FullDescription  string     `json:"full_description" db:"full_description"`
+	Reserved         bool       `json:"reserved" db:"reserved"`
+	TeamID           *uuid.UUID `json:"team_id,omitempty" db:"team_id"`
+	RequiresApproval bool       `json:"requires_approval" db:"requires_approval"`
+	CustodianID      *uuid.UUID `json:"custodian_id,omitempty" db:"custodian_id"`
+	Type             string     `json:"type,omitempty" db:"type" validate:"omitempty,lte=50"`
+	ArchivedAt       *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	LocationID       *uuid.UUID `json:"location_id,omitempty" db:"location_id"`
+	// Lifecycle status, only active equipment can be reserved
+	Status string `json:"status" db:"status"`
+	// Identical units pooled in one item, reservations book some of them
+	Quantity int `json:"quantity" db:"quantity" validate:"gte=0"`
+	// Both are unique, the asset tag is generated when not given
+	SerialNumber string `json:"serial_number,omitempty" db:"serial_number" validate:"omitempty,lte=100"`
+	AssetTag     string `json:"asset_tag,omitempty" db:"asset_tag" validate:"omitempty,lte=50"`
+	// Custom attributes, a JSON object validated against the schema of the equipment type
+	Attributes json.RawMessage `json:"attributes,omitempty" db:"attributes"`
+	// Only loaded for single equipment
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Update of equipment, fields left out keep their stored value. Fields which could be
+// cleared by an empty value are pointers here, team and custodian are cleared by the flags
+type EquipmentUpdate struct {
+	Equipment
+	FullDescription  *string `json:"full_description"`
+	RequiresApproval *bool   `json:"requires_approval"`
+	SerialNumber     *string `json:"serial_number" validate:"omitempty,lte=100"`
+	ClearTeam        bool    `json:"clear_team"`
+	ClearCustodian   bool    `json:"clear_custodian"`
+}
+
+// Restricts equipment listings
+type EquipmentFilter struct {
+	// Team scoped equipment is listed only for team members unless ViewAll is set
+	ViewerID uuid.UUID
+	ViewAll  bool
+	// Lists archived equipment instead of the catalog
+	Archived bool
+	// Lists equipment in the location or anywhere below it
+	LocationID *uuid.UUID
+	// Every attribute filter must match
+	Attributes []AttributeFilter
+}
+
+// Compares custom attribute Name with Value, Op is one of the Attribute operators
+type AttributeFilter struct {
+	Name  string `json:"name"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// JSON schema of the custom attributes of equipment of the type
+type EquipmentType struct {
+	Type      string          `json:"type" db:"type"`
+	Schema    json.RawMessage `json:"schema" db:"schema" validate:"required"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Individually tracked unit of pooled equipment
+type EquipmentUnit struct {
+	UnitID       uuid.UUID `json:"unit_id" db:"unit_id"`
+	EquipmentID  uuid.UUID `json:"equipment_id" db:"equipment_id"`
+	SerialNumber string    `json:"serial_number,omitempty" db:"serial_number" validate:"omitempty,lte=100"`
+	AssetTag     string    `json:"asset_tag,omitempty" db:"asset_tag" validate:"omitempty,lte=50"`
+	Status       string    `json:"status" db:"status" validate:"omitempty,oneof=active in_repair retired lost"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+type EquipmentStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active in_repair retired lost"`
+}
+
+// Equipment to print labels for, Skip leaves positions of a partly used sheet empty
+type LabelSheetRequest struct {
+	EquipmentIDs []uuid.UUID `json:"equipment_ids" validate:"required,min=1,max=500"`
+	Template     string      `json:"template"`
+	Skip         int         `json:"skip" validate:"gte=0"`
 }
 
 type EquipmentList struct {
@@ -41,3 +131,8 @@ type EquipmentWithUsersList struct {
 	HasMore    bool                 `json:"has_more"`
 	Equipments []EquipmentWithUsers `json:"equipments"`
 }
+
+// Custodian of the item and org admins may approve its reservations
+func (e *Equipment) CanApprove(user *User) bool {
+	return user.IsAdmin() || (e.CustodianID != nil && *e.CustodianID == user.UserID)
+}
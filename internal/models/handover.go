@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HandoverCheckOut = "check_out"
+	HandoverCheckIn  = "check_in"
+)
+
+// Physical pick up or return of reserved equipment, separate from the planned window
+type Handover struct {
+	Id            int       `json:"id" db:"id"`
+	ReservationID int       `json:"reservation_id" db:"reservation_id"`
+	EquipmentID   uuid.UUID `json:"equipment_id" db:"equipment_id"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	Kind          string    `json:"kind" db:"kind"`
+	HandledBy     uuid.UUID `json:"handled_by" db:"handled_by"`
+	Condition     string    `json:"condition,omitempty" db:"condition" validate:"omitempty,oneof=good worn damaged"`
+	Notes         string    `json:"notes,omitempty" db:"notes" validate:"lte=1000"`
+	// Where the equipment was put back on check in, moves it there
+	LocationID *uuid.UUID `json:"location_id,omitempty" db:"-"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// Identity of users stored in our own users table, subject is the user_id
+const LocalIdentityProvider = "local"
+
+// User identity asserted by an external identity provider
+type Identity struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	Login    string `json:"login"`
+	Email    string `json:"email"`
+	// Empty role keeps the role of already provisioned user unchanged
+	Role string `json:"role"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bundle of equipment which is reserved together, like a camera with its lens and batteries
+type Kit struct {
+	KitID       uuid.UUID `json:"kit_id" db:"kit_id"`
+	Name        string    `json:"name" db:"name" validate:"required,lte=100"`
+	Description string    `json:"description" db:"description" validate:"lte=500"`
+	Items       []KitItem `json:"items" validate:"required,min=1,max=50,dive"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type KitItem struct {
+	EquipmentID uuid.UUID `json:"equipment_id" db:"equipment_id" validate:"required"`
+	// Units of pooled equipment, one unless given
+	Quantity      int    `json:"quantity" db:"quantity" validate:"gte=0"`
+	EquipmentName string `json:"equipment_name,omitempty" db:"name"`
+}
+
+// Reservation of every kit component for the same window, made in full or not at all
+type KitReservation struct {
+	KitReservationID uuid.UUID        `json:"kit_reservation_id" db:"kit_reservation_id"`
+	KitID            uuid.UUID        `json:"kit_id" db:"kit_id"`
+	UserID           uuid.UUID        `json:"user_id" db:"user_id"`
+	ReservationStart time.Time        `json:"reservation_start" db:"reservation_start" validate:"required"`
+	ReservationEnd   time.Time        `json:"reservation_end" db:"reservation_end" validate:"required"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+	Reservations     []UsersEquipment `json:"reservations"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LocationSite     = "site"
+	LocationBuilding = "building"
+	LocationRoom     = "room"
+	LocationShelf    = "shelf"
+)
+
+// Depth of each kind in the hierarchy, a location is nested only in a shallower kind
+var locationLevels = map[string]int{
+	LocationSite:     0,
+	LocationBuilding: 1,
+	LocationRoom:     2,
+	LocationShelf:    3,
+}
+
+type Location struct {
+	LocationID  uuid.UUID  `json:"location_id" db:"location_id" validate:"omitempty"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty" db:"parent_id"`
+	Kind        string     `json:"kind" db:"kind" validate:"required,oneof=site building room shelf"`
+	Name        string     `json:"name" db:"name" validate:"required,lte=100"`
+	Description string     `json:"description" db:"description" validate:"lte=500"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	// Ancestors from the site down, only loaded for single location
+	Path []Location `json:"path,omitempty"`
+}
+
+// Location with everything nested in it
+type LocationNode struct {
+	Location
+	Children []LocationNode `json:"children"`
+}
+
+// Reports whether a location of the kind may be nested in a parent of parentKind,
+// top level locations have an empty parentKind and must be sites
+func LocationKindAllowed(kind string, parentKind string) bool {
+	level, ok := locationLevels[kind]
+	if !ok {
+		return false
+	}
+	if parentKind == "" {
+		return kind == LocationSite
+	}
+	parentLevel, ok := locationLevels[parentKind]
+	return ok && parentLevel < level
+}
+
+// Equipment moved between locations, by an edit or when returned on check in
+type LocationChange struct {
+	Id             int        `json:"id" db:"id"`
+	EquipmentID    uuid.UUID  `json:"equipment_id" db:"equipment_id"`
+	FromLocationID *uuid.UUID `json:"from_location_id,omitempty" db:"from_location_id"`
+	ToLocationID   *uuid.UUID `json:"to_location_id,omitempty" db:"to_location_id"`
+	ReservationID  *int       `json:"reservation_id,omitempty" db:"reservation_id"`
+	MovedBy        uuid.UUID  `json:"moved_by" db:"moved_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaintenanceScheduled = "scheduled"
+	MaintenanceCancelled = "cancelled"
+)
+
+// Window in which the equipment is serviced, it blocks reservations like another booking
+type Maintenance struct {
+	MaintenanceID int       `json:"maintenance_id" db:"maintenance_id"`
+	EquipmentID   uuid.UUID `json:"equipment_id" db:"equipment_id"`
+	WindowStart   time.Time `json:"window_start" db:"window_start" validate:"required"`
+	WindowEnd     time.Time `json:"window_end" db:"window_end" validate:"required"`
+	Reason        string    `json:"reason,omitempty" db:"reason" validate:"lte=500"`
+	Status        string    `json:"status" db:"status"`
+	CreatedBy     uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// Scheduled maintenance with the existing reservations it collides with, their owners are notified
+type MaintenanceSchedule struct {
+	Maintenance *Maintenance     `json:"maintenance"`
+	Collisions  []UsersEquipment `json:"collisions"`
+}
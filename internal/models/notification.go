@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationReservationCreated   = "reservation_created"
+	NotificationReservationApproved  = "reservation_approved"
+	NotificationReservationRejected  = "reservation_rejected"
+	NotificationReservationCancelled = "reservation_cancelled"
+	NotificationReservationShortened = "reservation_shortened"
+	NotificationReservationStarting  = "reservation_starting"
+	NotificationReservationEnding    = "reservation_ending"
+	NotificationReservationOverdue   = "reservation_overdue"
+	NotificationMaintenanceScheduled = "maintenance_scheduled"
+)
+
+var NotificationKinds = []string{
+	NotificationReservationCreated,
+	NotificationReservationApproved,
+	NotificationReservationRejected,
+	NotificationReservationCancelled,
+	NotificationReservationShortened,
+	NotificationReservationStarting,
+	NotificationReservationEnding,
+	NotificationReservationOverdue,
+	NotificationMaintenanceScheduled,
+}
+
+// Message for a user, Subject and Body are rendered from the template of its Kind
+type Notification struct {
+	NotificationID uuid.UUID          `json:"notification_id"`
+	UserID         uuid.UUID          `json:"user_id"`
+	Kind           string             `json:"kind"`
+	Subject        string             `json:"subject"`
+	Body           string             `json:"body"`
+	ReservationID  *int               `json:"reservation_id,omitempty"`
+	Params         NotificationParams `json:"-"`
+	CreatedAt      time.Time          `json:"created_at"`
+	ReadAt         *time.Time         `json:"read_at,omitempty"`
+}
+
+// Values available in notification templates
+type NotificationParams struct {
+	EquipmentName    string
+	ReservationStart time.Time
+	ReservationEnd   time.Time
+	Status           string
+	Reason           string
+}
+
+type NotificationList struct {
+	TotalCount    int            `json:"total_count"`
+	TotalPages    int            `json:"total_pages"`
+	Page          int            `json:"page"`
+	Size          int            `json:"size"`
+	HasMore       bool           `json:"has_more"`
+	Notifications []Notification `json:"notifications"`
+}
+
+// Channels a user receives a kind of notifications on, both are on unless changed
+type NotificationPreference struct {
+	Kind  string `json:"kind" db:"kind" validate:"required"`
+	Email bool   `json:"email" db:"email"`
+	InApp bool   `json:"in_app" db:"in_app"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Recurring reservation. ReservationStart and ReservationEnd describe the first
+// occurrence, the following ones are generated from Rule keeping the same duration
+type ReservationSeries struct {
+	SeriesID         uuid.UUID `json:"series_id" db:"series_id"`
+	UserID           uuid.UUID `json:"user_id" db:"user_id"`
+	EquipmentID      uuid.UUID `json:"equipment_id" db:"equipment_id" validate:"required"`
+	Rule             string    `json:"rule" db:"rule" validate:"required,lte=250"`
+	ReservationStart time.Time `json:"reservation_start" db:"reservation_start" validate:"required"`
+	ReservationEnd   time.Time `json:"reservation_end" db:"reservation_end" validate:"required"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	// Units of pooled equipment booked by every occurrence
+	Quantity    int              `json:"quantity" db:"-" validate:"gte=0"`
+	Occurrences []UsersEquipment `json:"occurrences"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Team struct {
+	TeamID      uuid.UUID `json:"team_id" db:"team_id" validate:"omitempty"`
+	Name        string    `json:"name" db:"name" validate:"required,lte=100"`
+	Description string    `json:"description" db:"description" validate:"lte=500"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type TeamList struct {
+	TotalCount int    `json:"total_count"`
+	TotalPages int    `json:"total_pages"`
+	Page       int    `json:"page"`
+	Size       int    `json:"size"`
+	HasMore    bool   `json:"has_more"`
+	Teams      []Team `json:"teams"`
+}
+
+type TeamMember struct {
+	TeamID   uuid.UUID `json:"team_id" db:"team_id"`
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	Login    string    `json:"login" db:"login"`
+	FullName string    `json:"full_name,omitempty" db:"full_name"`
+	IsAdmin  bool      `json:"is_admin" db:"is_admin"`
+}
@@ -2,16 +2,25 @@ package models
 
 import (
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const RoleAdmin = "admin"
+
 type User struct {
-	UserID   uuid.UUID `json:"user_id" db:"user_id" validate:"omitempty"`
-	Login    string    `json:"login" db:"login" validate:"required,lte=50"`
-	Password string    `json:"password,omitempty" db:"password" validate:"omitempty,required"`
-	Role     string    `json:"role,omitempty" db:"role" validate:"omitempty,lte=20"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id" validate:"omitempty"`
+	Login      string    `json:"login" db:"login" validate:"required,lte=50"`
+	Password   string    `json:"password,omitempty" db:"password" validate:"omitempty,required"`
+	Role       string    `json:"role,omitempty" db:"role" validate:"omitempty,lte=20"`
+	Email      string    `json:"email,omitempty" db:"email" validate:"omitempty,email,lte=100"`
+	FullName   string    `json:"full_name,omitempty" db:"full_name" validate:"omitempty,lte=100"`
+	Phone      string    `json:"phone,omitempty" db:"phone" validate:"omitempty,lte=30"`
+	Department string    `json:"department,omitempty" db:"department" validate:"omitempty,lte=100"`
+
+	EmailVerified bool `json:"email_verified" db:"email_verified"`
 }
 
 type UserList struct {
@@ -23,6 +32,10 @@ type UserList struct {
 	Users      []User `json:"users"`
 }
 
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
 func (u *User) HashPassword() error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -45,6 +58,10 @@ func (u *User) SanitizePassword() {
 
 func (u *User) PrepareCreate() error {
 	u.Password = strings.TrimSpace(u.Password)
+	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+	u.FullName = strings.TrimSpace(u.FullName)
+	u.Phone = strings.TrimSpace(u.Phone)
+	u.Department = strings.TrimSpace(u.Department)
 
 	if err := u.HashPassword(); err != nil {
 		return err
@@ -57,6 +74,11 @@ func (u *User) PrepareCreate() error {
 }
 
 func (u *User) PrepareUpdate() error {
+	u.Login = strings.TrimSpace(u.Login)
+	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+	u.FullName = strings.TrimSpace(u.FullName)
+	u.Phone = strings.TrimSpace(u.Phone)
+	u.Department = strings.TrimSpace(u.Department)
 
 	if u.Role != "" {
 		u.Role = strings.ToLower(strings.TrimSpace(u.Role))
@@ -64,6 +86,13 @@ func (u *User) PrepareUpdate() error {
 	return nil
 }
 
+type EmailVerification struct {
+	TokenHash string    `db:"token_hash"`
+	UserID    uuid.UUID `db:"user_id"`
+	Email     string    `db:"email"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
 type UserWithToken struct {
 	User         *User  `json:"user"`
 	AccessToken  string `json:"access_token"`
@@ -6,20 +6,47 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	ReservationPending   = "pending"
+	ReservationConfirmed = "confirmed"
+	ReservationRejected  = "rejected"
+	ReservationCancelled = "cancelled"
+	// ended while the equipment is still checked out
+	ReservationOverdue = "overdue"
+)
+
 type UsersEquipment struct {
-	Id               int       `json:"id" db:"id" validate:"omitempty"`
-	UserID           uuid.UUID `json:"user_id" db:"user_id"`
-	EquipmentID      uuid.UUID `json:"equipment_id" db:"equipment_id"`
-	ReservationStart time.Time `json:"reservation_start" db:"reservation_start"`
-	ReservationEnd   time.Time `json:"reservation_end" db:"reservation_end"`
+	Id               int        `json:"id" db:"id" validate:"omitempty"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id"`
+	EquipmentID      uuid.UUID  `json:"equipment_id" db:"equipment_id"`
+	ReservationStart time.Time  `json:"reservation_start" db:"reservation_start"`
+	ReservationEnd   time.Time  `json:"reservation_end" db:"reservation_end"`
+	Status           string     `json:"status" db:"status"`
+	RejectionReason  string     `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	SeriesID         *uuid.UUID `json:"series_id,omitempty" db:"series_id"`
+	// Units of pooled equipment, one unless given
+	Quantity int `json:"quantity" db:"quantity" validate:"gte=0"`
 }
 
 type ReservationInfo struct {
 	ReservationStart time.Time `json:"reservation_start" db:"reservation_start"`
 	ReservationEnd   time.Time `json:"reservation_end" db:"reservation_end"`
+	Status           string    `json:"status" db:"status"`
 }
 
 type ReservationInfoResponse struct {
 	Amount          int               `json:"amount"`
 	ReservationInfo []ReservationInfo `json:"data"`
 }
+
+// User's booking statistics checked by booking policies
+type BookingUsage struct {
+	ActiveReservations int     `json:"active_reservations"`
+	HoursInMonth       float64 `json:"hours_in_month"`
+}
+
+// Reservation picked up by a scheduled job
+type DueReservation struct {
+	UsersEquipment
+	EquipmentName string `json:"equipment_name"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WaitlistWaiting   = "waiting"
+	WaitlistAssigned  = "assigned"
+	WaitlistCancelled = "cancelled"
+)
+
+// Queue entry for a time window of equipment which is already taken.
+// Once the window frees up the reservation is made for the first eligible entry
+type WaitlistEntry struct {
+	Id            int       `json:"id" db:"id"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	EquipmentID   uuid.UUID `json:"equipment_id" db:"equipment_id" validate:"required"`
+	WindowStart   time.Time `json:"window_start" db:"window_start" validate:"required"`
+	WindowEnd     time.Time `json:"window_end" db:"window_end" validate:"required"`
+	Status        string    `json:"status" db:"status"`
+	ReservationID *int      `json:"reservation_id,omitempty" db:"reservation_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// Waitlist entry together with the waiter's role
+type WaitingEntry struct {
+	WaitlistEntry
+	Role string `json:"role"`
+}
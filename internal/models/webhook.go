@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventEquipmentCreated     = "equipment.created"
+	EventEquipmentUpdated     = "equipment.updated"
+	EventEquipmentDeleted     = "equipment.deleted"
+	EventEquipmentRestored    = "equipment.restored"
+	EventReservationCreated   = "reservation.created"
+	EventReservationApproved  = "reservation.approved"
+	EventReservationRejected  = "reservation.rejected"
+	EventReservationCancelled = "reservation.cancelled"
+	EventReservationShortened = "reservation.shortened"
+	EventUserRegistered       = "user.registered"
+	EventUserUpdated          = "user.updated"
+)
+
+var WebhookEvents = []string{
+	EventEquipmentCreated,
+	EventEquipmentUpdated,
+	EventEquipmentDeleted,
+	EventEquipmentRestored,
+	EventReservationCreated,
+	EventReservationApproved,
+	EventReservationRejected,
+	EventReservationCancelled,
+	EventReservationShortened,
+	EventUserRegistered,
+	EventUserUpdated,
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Subscription of an external URL to events, no Events means all of them.
+// Secret signs the payloads and is only returned when the webhook is created
+type Webhook struct {
+	WebhookID   uuid.UUID `json:"webhook_id" db:"webhook_id"`
+	URL         string    `json:"url" db:"url" validate:"required,url,lte=500"`
+	Description string    `json:"description" db:"description" validate:"lte=200"`
+	Secret      string    `json:"secret,omitempty" db:"secret" validate:"omitempty,gte=16,lte=100"`
+	Events      []string  `json:"events" db:"events"`
+	Active      bool      `json:"active" db:"active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+func (w *Webhook) Subscribed(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Body posted to webhooks
+type WebhookEvent struct {
+	EventID    uuid.UUID   `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// One event sent to one webhook, retried until it succeeds or runs out of attempts
+type WebhookDelivery struct {
+	DeliveryID     uuid.UUID       `json:"delivery_id" db:"delivery_id"`
+	WebhookID      uuid.UUID       `json:"webhook_id" db:"webhook_id"`
+	EventID        uuid.UUID       `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"`
+	Error          string          `json:"error,omitempty" db:"error"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
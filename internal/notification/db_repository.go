@@ -0,0 +1,20 @@
+package notification
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, n *models.Notification) error
+	GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, pq *utils.PaginationQuery) (*models.NotificationList, error)
+	MarkRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+
+	GetPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error)
+	SetPreference(ctx context.Context, userID uuid.UUID, pref *models.NotificationPreference) error
+	GetRecipient(ctx context.Context, userID uuid.UUID) (*models.User, error)
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"equiptrack/config"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/notification"
+	"equiptrack/internal/utils"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type notificationHandlers struct {
+	cfg            *config.Config
+	notificationUC notification.UseCase
+	logger         *logrus.Logger
+}
+
+// NewNotificationHandlers Notification handlers constructor
+func NewNotificationHandlers(cfg *config.Config, notificationUC notification.UseCase, log *logrus.Logger) notification.Handlers {
+	return &notificationHandlers{cfg: cfg, notificationUC: notificationUC, logger: log}
+}
+
+// Inbox of the current user, unread=true lists only unread notifications
+func (h *notificationHandlers) GetNotifications() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		unreadOnly := c.QueryParam("unread") == "true"
+
+		notifications, err := h.notificationUC.GetNotifications(c.Request().Context(), unreadOnly, paginationQuery)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, notifications)
+	}
+}
+
+func (h *notificationHandlers) MarkRead() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		nID, err := uuid.Parse(c.Param("notification_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.notificationUC.MarkRead(c.Request().Context(), nID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *notificationHandlers) MarkAllRead() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := h.notificationUC.MarkAllRead(c.Request().Context()); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *notificationHandlers) GetPreferences() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		prefs, err := h.notificationUC.GetPreferences(c.Request().Context())
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, prefs)
+	}
+}
+
+func (h *notificationHandlers) SetPreferences() echo.HandlerFunc {
+	type Preferences struct {
+		Preferences []models.NotificationPreference `json:"preferences" validate:"required,dive"`
+	}
+	return func(c echo.Context) error {
+		body := &Preferences{}
+		if err := utils.ReadRequest(c, body); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		prefs, err := h.notificationUC.SetPreferences(c.Request().Context(), body.Preferences)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, prefs)
+	}
+}
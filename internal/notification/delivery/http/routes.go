@@ -0,0 +1,21 @@
+package http
+
+import (
+	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
+	"equiptrack/internal/notification"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MapNotificationRoutes(notificationGroup *echo.Group, h notification.Handlers, mw *middleware.MiddlewareManager) {
+	read := mw.RequireScope(models.ScopeUsersRead)
+	write := mw.RequireScope(models.ScopeUsersWrite)
+
+	notificationGroup.Use(mw.AuthJWTMiddleware)
+	notificationGroup.GET("", h.GetNotifications(), read)
+	notificationGroup.POST("/read", h.MarkAllRead(), write)
+	notificationGroup.POST("/:notification_id/read", h.MarkRead(), write)
+	notificationGroup.GET("/preferences", h.GetPreferences(), read)
+	notificationGroup.PUT("/preferences", h.SetPreferences(), write)
+}
@@ -0,0 +1,11 @@
+package notification
+
+import (
+	"context"
+	"equiptrack/internal/models"
+)
+
+// Sends notification over every channel the recipient enabled for its kind
+type Notifier interface {
+	Notify(ctx context.Context, n *models.Notification) error
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/internal/models"
+	"equiptrack/internal/notification"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type notificationRepo struct {
+	db *sql.DB
+}
+
+// Notification Repository constructor
+func NewNotificationRepository(db *sql.DB) notification.Repository {
+	return &notificationRepo{db: db}
+}
+
+func (r *notificationRepo) Create(ctx context.Context, n *models.Notification) error {
+	if err := r.db.QueryRowContext(
+		ctx, qCreateNotification, n.UserID, n.Kind, n.Subject, n.Body, n.ReservationID,
+	).Scan(&n.NotificationID, &n.CreatedAt); err != nil {
+		return errors.Wrap(err, "notificationRepo.Create.QueryRowContext")
+	}
+	return nil
+}
+
+func (r *notificationRepo) GetNotifications(
+	ctx context.Context,
+	userID uuid.UUID,
+	unreadOnly bool,
+	pq *utils.PaginationQuery,
+) (*models.NotificationList, error) {
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, qGetTotal, userID, unreadOnly).Scan(&totalCount); err != nil {
+		return nil, errors.Wrap(err, "notificationRepo.GetNotifications.totalCount")
+	}
+
+	var notifications = make([]models.Notification, 0, pq.GetSize())
+	if totalCount > 0 {
+		rows, err := r.db.QueryContext(ctx, qGetNotifications, userID, unreadOnly, pq.GetOffset(), pq.GetLimit())
+		if err != nil {
+			return nil, errors.Wrap(err, "notificationRepo.GetNotifications.QueryContext")
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var n models.Notification
+			if err := rows.Scan(
+				&n.NotificationID,
+				&n.UserID,
+				&n.Kind,
+				&n.Subject,
+				&n.Body,
+				&n.ReservationID,
+				&n.CreatedAt,
+				&n.ReadAt,
+			); err != nil {
+				return nil, errors.Wrap(err, "notificationRepo.GetNotifications.QueryContext.ScanRows")
+			}
+			notifications = append(notifications, n)
+		}
+	}
+
+	return &models.NotificationList{
+		TotalCount:    totalCount,
+		TotalPages:    utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:          pq.GetPage(),
+		Size:          pq.GetSize(),
+		HasMore:       utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Notifications: notifications,
+	}, nil
+}
+
+func (r *notificationRepo) MarkRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qMarkRead, userID, notificationID)
+	if err != nil {
+		return errors.Wrap(err, "notificationRepo.MarkRead.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "notificationRepo.MarkRead.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "notificationRepo.MarkRead.rowsAffected")
+	}
+	return nil
+}
+
+func (r *notificationRepo) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, qMarkAllRead, userID); err != nil {
+		return errors.Wrap(err, "notificationRepo.MarkAllRead.ExecContext")
+	}
+	return nil
+}
+
+func (r *notificationRepo) GetPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	rows, err := r.db.QueryContext(ctx, qGetPreferences, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "notificationRepo.GetPreferences.QueryContext")
+	}
+	defer rows.Close()
+
+	var prefs = make([]models.NotificationPreference, 0)
+	for rows.Next() {
+		var p models.NotificationPreference
+		if err := rows.Scan(&p.Kind, &p.Email, &p.InApp); err != nil {
+			return nil, errors.Wrap(err, "notificationRepo.GetPreferences.QueryContext.ScanRows")
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, nil
+}
+
+func (r *notificationRepo) SetPreference(ctx context.Context, userID uuid.UUID, pref *models.NotificationPreference) error {
+	if _, err := r.db.ExecContext(ctx, qSetPreference, userID, pref.Kind, pref.Email, pref.InApp); err != nil {
+		return errors.Wrap(err, "notificationRepo.SetPreference.ExecContext")
+	}
+	return nil
+}
+
+func (r *notificationRepo) GetRecipient(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	u := &models.User{}
+	if err := r.db.QueryRowContext(ctx, qGetRecipient, userID).Scan(
+		&u.UserID, &u.Login, &u.Email, &u.EmailVerified,
+	); err != nil {
+		return nil, errors.Wrap(err, "notificationRepo.GetRecipient.QueryRowContext")
+	}
+	return u, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+const (
+	qCreateNotification = `INSERT INTO notifications (user_id, kind, subject, body, reservation_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING notification_id, created_at`
+	qGetTotal = `SELECT COUNT(notification_id)
+	FROM notifications
+	WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)`
+	qGetNotifications = `SELECT notification_id, user_id, kind, subject, body, reservation_id, created_at, read_at
+	FROM notifications
+	WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+	ORDER BY created_at DESC
+	OFFSET $3
+	LIMIT $4`
+	qMarkRead = `UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+	WHERE user_id = $1 AND notification_id = $2`
+	qMarkAllRead = `UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+	WHERE user_id = $1 AND read_at IS NULL`
+
+	qGetPreferences = `SELECT kind, email, in_app FROM notification_preferences WHERE user_id = $1`
+	qSetPreference  = `INSERT INTO notification_preferences (user_id, kind, email, in_app)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (user_id, kind) DO UPDATE SET email = EXCLUDED.email, in_app = EXCLUDED.in_app`
+	qGetRecipient = `SELECT user_id, login, COALESCE(email, ''), email_verified FROM users WHERE user_id = $1`
+)
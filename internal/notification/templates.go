@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"bytes"
+	"equiptrack/internal/models"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// Subjects are single lines, also in mail headers
+var subjectEscaper = strings.NewReplacer("\r", " ", "\n", " ")
+
+var funcs = template.FuncMap{
+	"time": func(t time.Time) string { return t.Format(time.RFC1123) },
+}
+
+var templates = map[string]messageTemplate{
+	models.NotificationReservationCreated: newTemplate(
+		"Reservation of {{.EquipmentName}}",
+		"Your reservation of {{.EquipmentName}} from {{time .ReservationStart}} to {{time .ReservationEnd}} is {{.Status}}.",
+	),
+	models.NotificationReservationApproved: newTemplate(
+		"Reservation of {{.EquipmentName}} approved",
+		"Your reservation of {{.EquipmentName}} from {{time .ReservationStart}} to {{time .ReservationEnd}} was approved.",
+	),
+	models.NotificationReservationRejected: newTemplate(
+		"Reservation of {{.EquipmentName}} rejected",
+		"Your reservation of {{.EquipmentName}} from {{time .ReservationStart}} to {{time .ReservationEnd}} was rejected."+
+			"{{if .Reason}} Reason: {{.Reason}}{{end}}",
+	),
+	models.NotificationReservationCancelled: newTemplate(
+		"Reservation of {{.EquipmentName}} cancelled",
+		"Your reservation of {{.EquipmentName}} from {{time .ReservationStart}} to {{time .ReservationEnd}} was cancelled.",
+	),
+	models.NotificationReservationShortened: newTemplate(
+		"Reservation of {{.EquipmentName}} shortened",
+		"Your reservation of {{.EquipmentName}} from {{time .ReservationStart}} now ends at {{time .ReservationEnd}}.",
+	),
+	models.NotificationReservationStarting: newTemplate(
+		"Reservation of {{.EquipmentName}} starts soon",
+		"Your reservation of {{.EquipmentName}} starts at {{time .ReservationStart}}.",
+	),
+	models.NotificationReservationEnding: newTemplate(
+		"Reservation of {{.EquipmentName}} ends soon",
+		"Your reservation of {{.EquipmentName}} ends at {{time .ReservationEnd}}, please return it in time.",
+	),
+	models.NotificationReservationOverdue: newTemplate(
+		"{{.EquipmentName}} is overdue",
+		"Your reservation of {{.EquipmentName}} ended at {{time .ReservationEnd}}, please return it.",
+	),
+	models.NotificationMaintenanceScheduled: newTemplate(
+		"{{.EquipmentName}} is under maintenance during your reservation",
+		"Maintenance of {{.EquipmentName}} was scheduled during your reservation from {{time .ReservationStart}} "+
+			"to {{time .ReservationEnd}}, please move or cancel it.{{if .Reason}} Reason: {{.Reason}}{{end}}",
+	),
+}
+
+func newTemplate(subject string, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New("subject").Funcs(funcs).Parse(subject)),
+		body:    template.Must(template.New("body").Funcs(funcs).Parse(body)),
+	}
+}
+
+// Fill Subject and Body of the notification from the template of its kind
+func Render(n *models.Notification) error {
+	t, ok := templates[n.Kind]
+	if !ok {
+		return fmt.Errorf("no template for notification kind %q", n.Kind)
+	}
+
+	var subject, body bytes.Buffer
+	if err := t.subject.Execute(&subject, n.Params); err != nil {
+		return err
+	}
+	if err := t.body.Execute(&body, n.Params); err != nil {
+		return err
+	}
+	n.Subject = subjectEscaper.Replace(subject.String())
+	n.Body = body.String()
+	return nil
+}
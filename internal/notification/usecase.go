@@ -0,0 +1,18 @@
+package notification
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type UseCase interface {
+	Notifier
+	GetNotifications(ctx context.Context, unreadOnly bool, pq *utils.PaginationQuery) (*models.NotificationList, error)
+	MarkRead(ctx context.Context, notificationID uuid.UUID) error
+	MarkAllRead(ctx context.Context) error
+	GetPreferences(ctx context.Context) ([]models.NotificationPreference, error)
+	SetPreferences(ctx context.Context, prefs []models.NotificationPreference) ([]models.NotificationPreference, error)
+}
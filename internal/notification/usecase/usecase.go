@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"equiptrack/config"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/mailer"
+	"equiptrack/internal/models"
+	"equiptrack/internal/notification"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type notificationUC struct {
+	cfg              *config.Config
+	notificationRepo notification.Repository
+	mailer           mailer.Mailer
+	logger           *logrus.Logger
+}
+
+func NewNotificationUseCase(
+	cfg *config.Config,
+	notificationRepo notification.Repository,
+	mailer mailer.Mailer,
+	log *logrus.Logger,
+) notification.UseCase {
+	return &notificationUC{cfg: cfg, notificationRepo: notificationRepo, mailer: mailer, logger: log}
+}
+
+// Delivers to the inbox and by email as the recipient's preferences allow. Email
+// goes only to verified addresses and is sent in the background, so a slow mail
+// server doesn't hold up the request. Its failures are only logged
+func (u *notificationUC) Notify(ctx context.Context, n *models.Notification) error {
+	if err := notification.Render(n); err != nil {
+		return errors.Wrap(err, "notificationUC.Notify.Render")
+	}
+	pref, err := u.getPreference(ctx, n.UserID, n.Kind)
+	if err != nil {
+		return err
+	}
+
+	if pref.Email {
+		msg := *n
+		go func() {
+			if err := u.sendEmail(context.WithoutCancel(ctx), &msg); err != nil {
+				u.logger.Errorf("notificationUC.Notify.sendEmail: %v", err)
+			}
+		}()
+	}
+	if pref.InApp {
+		return u.notificationRepo.Create(ctx, n)
+	}
+	return nil
+}
+
+func (u *notificationUC) sendEmail(ctx context.Context, n *models.Notification) error {
+	recipient, err := u.notificationRepo.GetRecipient(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	if recipient.Email == "" || !recipient.EmailVerified {
+		return nil
+	}
+	return u.mailer.Send(ctx, &mailer.Message{To: recipient.Email, Subject: n.Subject, Body: n.Body})
+}
+
+func (u *notificationUC) getPreference(ctx context.Context, userID uuid.UUID, kind string) (*models.NotificationPreference, error) {
+	prefs, err := u.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range prefs {
+		if p.Kind == kind {
+			return &p, nil
+		}
+	}
+	return &models.NotificationPreference{Kind: kind, Email: true, InApp: true}, nil
+}
+
+func (u *notificationUC) GetNotifications(
+	ctx context.Context,
+	unreadOnly bool,
+	pq *utils.PaginationQuery,
+) (*models.NotificationList, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.notificationRepo.GetNotifications(ctx, user.UserID, unreadOnly, pq)
+}
+
+func (u *notificationUC) MarkRead(ctx context.Context, notificationID uuid.UUID) error {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	return u.notificationRepo.MarkRead(ctx, user.UserID, notificationID)
+}
+
+func (u *notificationUC) MarkAllRead(ctx context.Context) error {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	return u.notificationRepo.MarkAllRead(ctx, user.UserID)
+}
+
+// Preferences of every kind, defaults filled in for kinds the user didn't change
+func (u *notificationUC) GetPreferences(ctx context.Context) ([]models.NotificationPreference, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := u.notificationRepo.GetPreferences(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	byKind := make(map[string]models.NotificationPreference, len(stored))
+	for _, p := range stored {
+		byKind[p.Kind] = p
+	}
+
+	prefs := make([]models.NotificationPreference, 0, len(models.NotificationKinds))
+	for _, kind := range models.NotificationKinds {
+		p, ok := byKind[kind]
+		if !ok {
+			p = models.NotificationPreference{Kind: kind, Email: true, InApp: true}
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, nil
+}
+
+func (u *notificationUC) SetPreferences(
+	ctx context.Context,
+	prefs []models.NotificationPreference,
+) ([]models.NotificationPreference, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range prefs {
+		if !isKnownKind(prefs[i].Kind) {
+			return nil, httpErrors.NewBadRequestError("unknown notification kind " + prefs[i].Kind)
+		}
+	}
+	for i := range prefs {
+		if err := u.notificationRepo.SetPreference(ctx, user.UserID, &prefs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return u.GetPreferences(ctx)
+}
+
+func isKnownKind(kind string) bool {
+	for _, k := range models.NotificationKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
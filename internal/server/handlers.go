@@ -1,15 +1,45 @@
 package server
 
 import (
+	apiKeyHttp "equiptrack/internal/apikey/delivery/http"
+	apiKeyRepository "equiptrack/internal/apikey/repository"
+	apiKeyUseCase "equiptrack/internal/apikey/usecase"
+	authenticator "equiptrack/internal/auth/authenticator"
 	authHttp "equiptrack/internal/auth/delivery/http"
+	authOIDC "equiptrack/internal/auth/oidc"
 	authRepository "equiptrack/internal/auth/repository"
 	authUseCase "equiptrack/internal/auth/usecase"
+
+	auditHttp "equiptrack/internal/audit/delivery/http"
+	auditRepository "equiptrack/internal/audit/repository"
+	auditUseCase "equiptrack/internal/audit/usecase"
+
+	calendarHttp "equiptrack/internal/calendar/delivery/http"
+	calendarRepository "equiptrack/internal/calendar/repository"
+	calendarUseCase "equiptrack/internal/calendar/usecase"
+	locationHttp "equiptrack/internal/location/delivery/http"
+	locationRepository "equiptrack/internal/location/repository"
+	locationUseCase "equiptrack/internal/location/usecase"
+	"equiptrack/internal/mailer"
 	apiMiddlewares "equiptrack/internal/middleware"
+	"equiptrack/internal/storage"
 
 	equipHttp "equiptrack/internal/equipment/delivery/http"
 	equipRepository "equiptrack/internal/equipment/repository"
 	equipUseCase "equiptrack/internal/equipment/usecase"
 
+	notificationHttp "equiptrack/internal/notification/delivery/http"
+	notificationRepository "equiptrack/internal/notification/repository"
+	notificationUseCase "equiptrack/internal/notification/usecase"
+
+	teamHttp "equiptrack/internal/team/delivery/http"
+	teamRepository "equiptrack/internal/team/repository"
+	teamUseCase "equiptrack/internal/team/usecase"
+
+	webhookHttp "equiptrack/internal/webhook/delivery/http"
+	webhookRepository "equiptrack/internal/webhook/repository"
+	webhookUseCase "equiptrack/internal/webhook/usecase"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -19,16 +49,54 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 	// Init repositories
 	aRepo := authRepository.NewAuthRepository(s.db)
 	eRepo := equipRepository.NewEquipmentRepository(s.db)
+	kRepo := apiKeyRepository.NewAPIKeyRepository(s.db)
+	tRepo := teamRepository.NewTeamRepository(s.db)
+	cRepo := calendarRepository.NewCalendarRepository(s.db)
+	nRepo := notificationRepository.NewNotificationRepository(s.db)
+	wRepo := webhookRepository.NewWebhookRepository(s.db)
+	auRepo := auditRepository.NewAuditRepository(s.db)
+	lRepo := locationRepository.NewLocationRepository(s.db)
+
+	mail := mailer.NewMailer(s.cfg, s.logger)
+	authenticators, err := authenticator.NewAuthenticators(s.cfg, aRepo)
+	if err != nil {
+		return err
+	}
+	store, err := storage.NewStorage(s.cfg)
+	if err != nil {
+		return err
+	}
 
 	// Init useCases
-	authUC := authUseCase.NewAuthUseCase(s.cfg, aRepo, s.logger)
-	equipUC := equipUseCase.NewEquipmentUseCase(s.cfg, eRepo, s.logger)
+	auditUC := auditUseCase.NewAuditUseCase(s.cfg, auRepo, s.logger)
+	webhookUC := webhookUseCase.NewWebhookUseCase(s.cfg, wRepo, auditUC, s.logger)
+	authUC := authUseCase.NewAuthUseCase(s.cfg, aRepo, authenticators, mail, webhookUC, auditUC, s.logger)
+	notificationUC := notificationUseCase.NewNotificationUseCase(s.cfg, nRepo, mail, s.logger)
+	equipUC := equipUseCase.NewEquipmentUseCase(s.cfg, eRepo, notificationUC, webhookUC, auditUC, store, s.logger)
+	apiKeyUC := apiKeyUseCase.NewAPIKeyUseCase(s.cfg, kRepo, auditUC, s.logger)
+	teamUC := teamUseCase.NewTeamUseCase(s.cfg, tRepo, auditUC, s.logger)
+	calendarUC := calendarUseCase.NewCalendarUseCase(s.cfg, cRepo, equipUC, auditUC, s.logger)
+	locationUC := locationUseCase.NewLocationUseCase(s.cfg, lRepo, auditUC, s.logger)
+
+	s.scheduler = newScheduler(&s.cfg.Scheduler, s.db, s.logger)
+	s.scheduler.add("overdue", equipUC.ProcessOverdue)
+	s.scheduler.add("reminders", equipUC.SendReminders)
+	s.scheduler.add("webhooks", webhookUC.ProcessDueDeliveries)
+
+	oidcProvider := authOIDC.NewOIDCProvider(&s.cfg.OIDC)
 
 	// Init handlers
-	authHandlers := authHttp.NewAuthHandlers(s.cfg, authUC, s.logger)
+	authHandlers := authHttp.NewAuthHandlers(s.cfg, authUC, oidcProvider, s.logger)
 	equipmentHandlers := equipHttp.NewEquipmentHandlers(s.cfg, equipUC, s.logger)
+	apiKeyHandlers := apiKeyHttp.NewAPIKeyHandlers(s.cfg, apiKeyUC, s.logger)
+	teamHandlers := teamHttp.NewTeamHandlers(s.cfg, teamUC, s.logger)
+	calendarHandlers := calendarHttp.NewCalendarHandlers(s.cfg, calendarUC, s.logger)
+	notificationHandlers := notificationHttp.NewNotificationHandlers(s.cfg, notificationUC, s.logger)
+	webhookHandlers := webhookHttp.NewWebhookHandlers(s.cfg, webhookUC, s.logger)
+	auditHandlers := auditHttp.NewAuditHandlers(s.cfg, auditUC, s.logger)
+	locationHandlers := locationHttp.NewLocationHandlers(s.cfg, locationUC, s.logger)
 
-	mw := apiMiddlewares.NewMiddlewareManager(authUC, s.cfg, []string{"*"}, s.logger)
+	mw := apiMiddlewares.NewMiddlewareManager(authUC, apiKeyUC, s.cfg, []string{"*"}, s.logger)
 
 	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		StackSize:         1 << 10, // 1 KB
@@ -36,6 +104,7 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 		DisableStackAll:   true,
 	}))
 	e.Use(middleware.RequestID())
+	e.Use(mw.RequestMetaMiddleware)
 
 	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: 5,
@@ -48,9 +117,23 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 
 	authGroup := v1.Group("/auth")
 	equipmentGroup := v1.Group("/equipment")
+	serviceAccountGroup := v1.Group("/service_accounts")
+	teamGroup := v1.Group("/teams")
+	calendarGroup := v1.Group("/calendar")
+	notificationGroup := v1.Group("/notifications")
+	webhookGroup := v1.Group("/webhooks")
+	auditGroup := v1.Group("/audit")
+	locationGroup := v1.Group("/locations")
 
 	authHttp.MapAuthRoutes(authGroup, authHandlers, mw)
 	equipHttp.MapEquipmentRoutes(equipmentGroup, equipmentHandlers, mw)
+	apiKeyHttp.MapServiceAccountRoutes(serviceAccountGroup, apiKeyHandlers, mw)
+	teamHttp.MapTeamRoutes(teamGroup, teamHandlers, mw)
+	calendarHttp.MapCalendarRoutes(calendarGroup, calendarHandlers, mw)
+	notificationHttp.MapNotificationRoutes(notificationGroup, notificationHandlers, mw)
+	webhookHttp.MapWebhookRoutes(webhookGroup, webhookHandlers, mw)
+	auditHttp.MapAuditRoutes(auditGroup, auditHandlers, mw)
+	locationHttp.MapLocationRoutes(locationGroup, locationHandlers, mw)
 
 	return nil
 }
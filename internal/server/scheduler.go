@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/config"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Advisory lock key held by the replica running the jobs
+const schedulerLockKey = 4242001
+
+type job struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Runs background jobs periodically. Every replica runs its own scheduler, a Postgres
+// advisory lock makes sure only one of them executes the jobs in each round
+type scheduler struct {
+	cfg    *config.SchedulerConfig
+	db     *sql.DB
+	jobs   []job
+	logger *logrus.Logger
+}
+
+func newScheduler(cfg *config.SchedulerConfig, db *sql.DB, logger *logrus.Logger) *scheduler {
+	return &scheduler{cfg: cfg, db: db, logger: logger}
+}
+
+func (s *scheduler) add(name string, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, job{name: name, run: run})
+}
+
+// Blocks until ctx is cancelled
+func (s *scheduler) start(ctx context.Context) {
+	interval := time.Second * s.cfg.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.runOnce(ctx); err != nil {
+			s.logger.Errorf("scheduler: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *scheduler) runOnce(ctx context.Context) error {
+	// session level advisory locks belong to a connection, so lock and unlock
+	// must go through the same one instead of the pool
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "scheduler.runOnce.Conn")
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, schedulerLockKey).Scan(&locked); err != nil {
+		return errors.Wrap(err, "scheduler.runOnce.Lock")
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		// unlock even when ctx is already cancelled, the connection goes back to the pool
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, schedulerLockKey); err != nil {
+			s.logger.Errorf("scheduler: unlock: %v", err)
+		}
+	}()
+
+	for _, j := range s.jobs {
+		if err := j.run(ctx); err != nil {
+			s.logger.Errorf("scheduler: job %s: %v", j.name, err)
+		}
+	}
+	return nil
+}
@@ -19,10 +19,11 @@ const (
 )
 
 type Server struct {
-	echo   *echo.Echo
-	cfg    *config.Config
-	db     *sql.DB
-	logger *logrus.Logger
+	echo      *echo.Echo
+	cfg       *config.Config
+	db        *sql.DB
+	scheduler *scheduler
+	logger    *logrus.Logger
 }
 
 func NewServer(cfg *config.Config, db *sql.DB, logger *logrus.Logger) *Server {
@@ -54,10 +55,17 @@ func (s *Server) Run() error {
 		return err
 	}
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if s.cfg.Scheduler.Enabled {
+		go s.scheduler.start(jobsCtx)
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	<-quit
+	stopJobs()
 
 	ctx, shutdown := context.WithTimeout(context.Background(), ctxTimeout*time.Second)
 	defer shutdown()
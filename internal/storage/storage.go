@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"equiptrack/config"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const DriverLocal = "local"
+
+// Returned by Get for keys which aren't stored
+var ErrNotFound = errors.New("storage: object not found")
+
+// Blob storage for uploaded files. Keys are slash separated paths
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage selects the configured storage driver
+func NewStorage(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case DriverLocal, "":
+		return NewLocalStorage(cfg.Storage.LocalPath)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
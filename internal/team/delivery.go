@@ -0,0 +1,15 @@
+package team
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	GetTeams() echo.HandlerFunc
+
+	GetMembers() echo.HandlerFunc
+	SetMember() echo.HandlerFunc
+	DeleteMember() echo.HandlerFunc
+}
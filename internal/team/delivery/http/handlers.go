@@ -0,0 +1,185 @@
+package http
+
+import (
+	"equiptrack/config"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/team"
+	"equiptrack/internal/utils"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type teamHandlers struct {
+	cfg    *config.Config
+	teamUC team.UseCase
+	logger *logrus.Logger
+}
+
+// NewTeamHandlers Team handlers constructor
+func NewTeamHandlers(cfg *config.Config, teamUC team.UseCase, log *logrus.Logger) team.Handlers {
+	return &teamHandlers{cfg: cfg, teamUC: teamUC, logger: log}
+}
+
+func (h *teamHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		t := &models.Team{}
+		if err := utils.ReadRequest(c, t); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		createdTeam, err := h.teamUC.Create(c.Request().Context(), t)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusCreated, createdTeam)
+	}
+}
+
+func (h *teamHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tID, err := uuid.Parse(c.Param("team_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		t := &models.Team{}
+		if err := utils.ReadRequest(c, t); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+		t.TeamID = tID
+
+		if err = h.teamUC.Update(c.Request().Context(), t); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *teamHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tID, err := uuid.Parse(c.Param("team_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.teamUC.Delete(c.Request().Context(), tID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *teamHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tID, err := uuid.Parse(c.Param("team_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		t, err := h.teamUC.GetByID(c.Request().Context(), tID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, t)
+	}
+}
+
+func (h *teamHandlers) GetTeams() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		teamList, err := h.teamUC.GetTeams(c.Request().Context(), paginationQuery)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, teamList)
+	}
+}
+
+func (h *teamHandlers) GetMembers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tID, err := uuid.Parse(c.Param("team_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		members, err := h.teamUC.GetMembers(c.Request().Context(), tID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, members)
+	}
+}
+
+func (h *teamHandlers) SetMember() echo.HandlerFunc {
+	type Member struct {
+		IsAdmin bool `json:"is_admin"`
+	}
+	return func(c echo.Context) error {
+		tID, err := uuid.Parse(c.Param("team_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		uID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		member := &Member{}
+		if err := c.Bind(member); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		if err = h.teamUC.SetMember(c.Request().Context(), &models.TeamMember{
+			TeamID:  tID,
+			UserID:  uID,
+			IsAdmin: member.IsAdmin,
+		}); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *teamHandlers) DeleteMember() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tID, err := uuid.Parse(c.Param("team_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+		uID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = h.teamUC.DeleteMember(c.Request().Context(), tID, uID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
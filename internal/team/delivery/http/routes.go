@@ -0,0 +1,21 @@
+package http
+
+import (
+	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
+	"equiptrack/internal/team"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MapTeamRoutes(teamGroup *echo.Group, h team.Handlers, mw *middleware.MiddlewareManager) {
+	teamGroup.Use(mw.AuthJWTMiddleware)
+	teamGroup.POST("", h.Create(), mw.IsAdminMiddleware, mw.RequireScope(models.ScopeUsersWrite))
+	teamGroup.GET("", h.GetTeams(), mw.RequireScope(models.ScopeUsersRead))
+	teamGroup.GET("/:team_id", h.GetByID(), mw.RequireScope(models.ScopeUsersRead))
+	teamGroup.PUT("/:team_id", h.Update(), mw.RequireScope(models.ScopeUsersWrite))
+	teamGroup.DELETE("/:team_id", h.Delete(), mw.IsAdminMiddleware, mw.RequireScope(models.ScopeUsersWrite))
+	teamGroup.GET("/:team_id/members", h.GetMembers(), mw.RequireScope(models.ScopeUsersRead))
+	teamGroup.PUT("/:team_id/members/:user_id", h.SetMember(), mw.RequireScope(models.ScopeUsersWrite))
+	teamGroup.DELETE("/:team_id/members/:user_id", h.DeleteMember(), mw.RequireScope(models.ScopeUsersWrite))
+}
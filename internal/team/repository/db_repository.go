@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/internal/models"
+	"equiptrack/internal/team"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type teamRepo struct {
+	db *sql.DB
+}
+
+// Team Repository constructor
+func NewTeamRepository(db *sql.DB) team.Repository {
+	return &teamRepo{db: db}
+}
+
+func (r *teamRepo) Create(ctx context.Context, team *models.Team) (*models.Team, error) {
+	t := &models.Team{}
+	if err := r.db.QueryRowContext(ctx, qCreateTeam, team.Name, team.Description).Scan(
+		&t.TeamID, &t.Name, &t.Description, &t.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "teamRepo.Create.QueryRowContext")
+	}
+	return t, nil
+}
+
+func (r *teamRepo) Update(ctx context.Context, team *models.Team) error {
+	result, err := r.db.ExecContext(ctx, qUpdateTeam, team.Name, team.Description, team.TeamID)
+	if err != nil {
+		return errors.Wrap(err, "teamRepo.Update.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "teamRepo.Update.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "teamRepo.Update.rowsAffected")
+	}
+	return nil
+}
+
+func (r *teamRepo) Delete(ctx context.Context, teamID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qDeleteTeam, teamID)
+	if err != nil {
+		return errors.WithMessage(err, "teamRepo.Delete.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "teamRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "teamRepo.Delete.rowsAffected")
+	}
+	return nil
+}
+
+func (r *teamRepo) GetByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
+	t := &models.Team{}
+	if err := r.db.QueryRowContext(ctx, qGetTeam, teamID).Scan(
+		&t.TeamID, &t.Name, &t.Description, &t.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "teamRepo.GetByID.QueryRowContext")
+	}
+	return t, nil
+}
+
+func (r *teamRepo) GetTeams(ctx context.Context, pq *utils.PaginationQuery) (*models.TeamList, error) {
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, qGetTotal).Scan(&totalCount); err != nil {
+		return nil, errors.Wrap(err, "teamRepo.GetTeams.totalCount")
+	}
+	return r.getTeamList(ctx, pq, totalCount, qGetTeams, pq.GetOffset(), pq.GetLimit())
+}
+
+func (r *teamRepo) GetUserTeams(ctx context.Context, pq *utils.PaginationQuery, userID uuid.UUID) (*models.TeamList, error) {
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, qGetTotalUserTeams, userID).Scan(&totalCount); err != nil {
+		return nil, errors.Wrap(err, "teamRepo.GetUserTeams.totalCount")
+	}
+	return r.getTeamList(ctx, pq, totalCount, qGetUserTeams, pq.GetOffset(), pq.GetLimit(), userID)
+}
+
+func (r *teamRepo) getTeamList(
+	ctx context.Context,
+	pq *utils.PaginationQuery,
+	totalCount int,
+	query string,
+	args ...interface{},
+) (*models.TeamList, error) {
+	if totalCount == 0 {
+		return &models.TeamList{
+			TotalCount: totalCount,
+			TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+			Page:       pq.GetPage(),
+			Size:       pq.GetSize(),
+			HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+			Teams:      make([]models.Team, 0),
+		}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "teamRepo.getTeamList.QueryContext")
+	}
+	defer rows.Close()
+
+	var teams = make([]models.Team, 0, pq.GetSize())
+	for rows.Next() {
+		var t models.Team
+		if err := rows.Scan(&t.TeamID, &t.Name, &t.Description, &t.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "teamRepo.getTeamList.QueryContext.ScanRows")
+		}
+		teams = append(teams, t)
+	}
+
+	return &models.TeamList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Teams:      teams,
+	}, nil
+}
+
+func (r *teamRepo) GetMembers(ctx context.Context, teamID uuid.UUID) ([]models.TeamMember, error) {
+	rows, err := r.db.QueryContext(ctx, qGetMembers, teamID)
+	if err != nil {
+		return nil, errors.Wrap(err, "teamRepo.GetMembers.QueryContext")
+	}
+	defer rows.Close()
+
+	var members = make([]models.TeamMember, 0)
+	for rows.Next() {
+		var m models.TeamMember
+		if err := rows.Scan(&m.TeamID, &m.UserID, &m.Login, &m.FullName, &m.IsAdmin); err != nil {
+			return nil, errors.Wrap(err, "teamRepo.GetMembers.QueryContext.ScanRows")
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func (r *teamRepo) GetMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (*models.TeamMember, error) {
+	m := &models.TeamMember{}
+	if err := r.db.QueryRowContext(ctx, qGetMember, teamID, userID).Scan(
+		&m.TeamID, &m.UserID, &m.Login, &m.FullName, &m.IsAdmin,
+	); err != nil {
+		return nil, errors.Wrap(err, "teamRepo.GetMember.QueryRowContext")
+	}
+	return m, nil
+}
+
+func (r *teamRepo) SetMember(ctx context.Context, member *models.TeamMember) error {
+	if _, err := r.db.ExecContext(ctx, qSetMember, member.TeamID, member.UserID, member.IsAdmin); err != nil {
+		return errors.Wrap(err, "teamRepo.SetMember.ExecContext")
+	}
+	return nil
+}
+
+func (r *teamRepo) DeleteMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qDeleteMember, teamID, userID)
+	if err != nil {
+		return errors.Wrap(err, "teamRepo.DeleteMember.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "teamRepo.DeleteMember.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "teamRepo.DeleteMember.rowsAffected")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+const (
+	qCreateTeam = `INSERT INTO teams (name, description) VALUES ($1, $2) RETURNING team_id, name, description, created_at`
+	qUpdateTeam = `UPDATE teams SET name = $1, description = $2 WHERE team_id = $3`
+	qDeleteTeam = `DELETE FROM teams WHERE team_id = $1`
+	qGetTeam    = `SELECT team_id, name, description, created_at FROM teams WHERE team_id = $1`
+
+	qGetTotal = `SELECT COUNT(team_id) FROM teams`
+	qGetTeams = `SELECT team_id, name, description, created_at
+	FROM teams
+	ORDER BY name
+	OFFSET $1
+	LIMIT $2`
+
+	qGetTotalUserTeams = `SELECT COUNT(team_id) FROM team_members WHERE user_id = $1`
+	qGetUserTeams      = `SELECT team_id, name, description, created_at
+	FROM teams
+	INNER JOIN team_members using(team_id)
+	WHERE user_id = $3
+	ORDER BY name
+	OFFSET $1
+	LIMIT $2`
+
+	qGetMembers = `SELECT team_id, user_id, login, full_name, is_admin
+	FROM team_members
+	INNER JOIN users using(user_id)
+	WHERE team_id = $1
+	ORDER BY login`
+	qGetMember = `SELECT team_id, user_id, login, full_name, is_admin
+	FROM team_members
+	INNER JOIN users using(user_id)
+	WHERE team_id = $1 AND user_id = $2`
+	qSetMember = `INSERT INTO team_members (team_id, user_id, is_admin) VALUES ($1, $2, $3)
+	ON CONFLICT (team_id, user_id) DO UPDATE SET is_admin = EXCLUDED.is_admin`
+	qDeleteMember = `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`
+)
@@ -0,0 +1,21 @@
+package team
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type UseCase interface {
+	Create(ctx context.Context, team *models.Team) (*models.Team, error)
+	Update(ctx context.Context, team *models.Team) error
+	Delete(ctx context.Context, teamID uuid.UUID) error
+	GetByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error)
+	GetTeams(ctx context.Context, pq *utils.PaginationQuery) (*models.TeamList, error)
+
+	GetMembers(ctx context.Context, teamID uuid.UUID) ([]models.TeamMember, error)
+	SetMember(ctx context.Context, member *models.TeamMember) error
+	DeleteMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error
+}
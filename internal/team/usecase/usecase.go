@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/config"
+	"equiptrack/internal/audit"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/team"
+	"equiptrack/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type teamUC struct {
+	cfg      *config.Config
+	teamRepo team.Repository
+	auditor  audit.Recorder
+	logger   *logrus.Logger
+}
+
+func NewTeamUseCase(cfg *config.Config, teamRepo team.Repository, auditor audit.Recorder, log *logrus.Logger) team.UseCase {
+	return &teamUC{cfg: cfg, teamRepo: teamRepo, auditor: auditor, logger: log}
+}
+
+func (u *teamUC) Create(ctx context.Context, team *models.Team) (*models.Team, error) {
+	created, err := u.teamRepo.Create(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityTeam, created.TeamID.String(), nil, created)
+	return created, nil
+}
+
+func (u *teamUC) Update(ctx context.Context, team *models.Team) error {
+	if err := u.checkTeamAdmin(ctx, team.TeamID); err != nil {
+		return err
+	}
+	before, err := u.teamRepo.GetByID(ctx, team.TeamID)
+	if err != nil {
+		return err
+	}
+	if err = u.teamRepo.Update(ctx, team); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditUpdate, models.AuditEntityTeam, team.TeamID.String(), before, team)
+	return nil
+}
+
+func (u *teamUC) Delete(ctx context.Context, teamID uuid.UUID) error {
+	before, err := u.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if err = u.teamRepo.Delete(ctx, teamID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityTeam, teamID.String(), before, nil)
+	return nil
+}
+
+func (u *teamUC) GetByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
+	if err := u.checkMember(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return u.teamRepo.GetByID(ctx, teamID)
+}
+
+// Org admins see all teams, everybody else only the teams they belong to
+func (u *teamUC) GetTeams(ctx context.Context, pq *utils.PaginationQuery) (*models.TeamList, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin() {
+		return u.teamRepo.GetTeams(ctx, pq)
+	}
+	return u.teamRepo.GetUserTeams(ctx, pq, user.UserID)
+}
+
+func (u *teamUC) GetMembers(ctx context.Context, teamID uuid.UUID) ([]models.TeamMember, error) {
+	if err := u.checkMember(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return u.teamRepo.GetMembers(ctx, teamID)
+}
+
+func (u *teamUC) SetMember(ctx context.Context, member *models.TeamMember) error {
+	if err := u.checkTeamAdmin(ctx, member.TeamID); err != nil {
+		return err
+	}
+	before, err := u.teamRepo.GetMember(ctx, member.TeamID, member.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err = u.teamRepo.SetMember(ctx, member); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditSetMember, models.AuditEntityTeam, member.TeamID.String(), before, member)
+	return nil
+}
+
+func (u *teamUC) DeleteMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error {
+	if err := u.checkTeamAdmin(ctx, teamID); err != nil {
+		return err
+	}
+	before, err := u.teamRepo.GetMember(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if err = u.teamRepo.DeleteMember(ctx, teamID, userID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditDeleteMember, models.AuditEntityTeam, teamID.String(), before, nil)
+	return nil
+}
+
+func (u *teamUC) checkMember(ctx context.Context, teamID uuid.UUID) error {
+	_, err := u.getMembership(ctx, teamID)
+	return err
+}
+
+func (u *teamUC) checkTeamAdmin(ctx context.Context, teamID uuid.UUID) error {
+	member, err := u.getMembership(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if member != nil && !member.IsAdmin {
+		return httpErrors.NewForbiddenError("not a team admin")
+	}
+	return nil
+}
+
+// Returns nil membership for org admins, who may manage any team
+func (u *teamUC) getMembership(ctx context.Context, teamID uuid.UUID) (*models.TeamMember, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin() {
+		return nil, nil
+	}
+
+	member, err := u.teamRepo.GetMember(ctx, teamID, user.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, httpErrors.NewForbiddenError("not a team member")
+		}
+		return nil, err
+	}
+	return member, nil
+}
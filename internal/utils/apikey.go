@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	apiKeyPrefix    = "eqt"
+	apiKeyIDBytes   = 4
+	apiKeySecretLen = 32
+)
+
+// Generate new API key, returns lookup prefix and the full key
+func NewAPIKey() (string, string, error) {
+	id := make([]byte, apiKeyIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, apiKeySecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	prefix := apiKeyPrefix + "_" + hex.EncodeToString(id)
+	return prefix, prefix + "_" + hex.EncodeToString(secret), nil
+}
+
+// Get lookup prefix from the full API key
+func ParseAPIKeyPrefix(key string) (string, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || len(parts[1]) != apiKeyIDBytes*2 {
+		return "", errors.New("malformed API key")
+	}
+	return parts[0] + "_" + parts[1], nil
+}
+
+// API keys are random, so plain sha256 is enough to keep them safe at rest
+func HashAPIKey(key string) string {
+	return HashToken(key)
+}
+
+func CompareAPIKey(hash string, key string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashAPIKey(key))) == 1
+}
@@ -20,6 +20,28 @@ func GetUserFromCtx(ctx context.Context) (*models.User, error) {
 	return user, nil
 }
 
+type APIKeyCtxKey struct{}
+
+// Returns API key the request was authenticated with, if any
+func GetAPIKeyFromCtx(ctx context.Context) (*models.APIKey, bool) {
+	key, ok := ctx.Value(APIKeyCtxKey{}).(*models.APIKey)
+	return key, ok
+}
+
+type RequestMetaCtxKey struct{}
+
+// Request metadata recorded alongside the operations it triggers
+type RequestMeta struct {
+	RequestID string
+	IPAddress string
+}
+
+// Returns metadata of the request in progress, empty outside of one
+func GetRequestMetaFromCtx(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(RequestMetaCtxKey{}).(RequestMeta)
+	return meta
+}
+
 func GetRequestID(c echo.Context) string {
 	return c.Response().Header().Get(echo.HeaderXRequestID)
 }
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"equiptrack/config"
+	"strings"
+)
+
+// Map external groups to a role using the first matching mapping
+func MapGroupsToRole(mappings []config.RoleMapping, groups []string, defaultRole string) string {
+	if len(mappings) == 0 {
+		return ""
+	}
+	for _, m := range mappings {
+		for _, g := range groups {
+			if strings.EqualFold(m.Group, g) {
+				return m.Role
+			}
+		}
+	}
+	return defaultRole
+}
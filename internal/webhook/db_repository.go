@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"context"
+	"equiptrack/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, webhookID uuid.UUID) error
+	GetByID(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetActiveWebhooks(ctx context.Context) ([]models.Webhook, error)
+
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, deliveryID uuid.UUID) (*models.WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, deliveryID uuid.UUID, lease time.Duration) (bool, error)
+	ClaimDueDeliveries(ctx context.Context, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"equiptrack/config"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"equiptrack/internal/webhook"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type webhookHandlers struct {
+	cfg       *config.Config
+	webhookUC webhook.UseCase
+	logger    *logrus.Logger
+}
+
+// NewWebhookHandlers Webhook handlers constructor
+func NewWebhookHandlers(cfg *config.Config, webhookUC webhook.UseCase, log *logrus.Logger) webhook.Handlers {
+	return &webhookHandlers{cfg: cfg, webhookUC: webhookUC, logger: log}
+}
+
+func (h *webhookHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		w := &models.Webhook{}
+		if err := utils.ReadRequest(c, w); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		created, err := h.webhookUC.Create(c.Request().Context(), w)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *webhookHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		wID, err := uuid.Parse(c.Param("webhook_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		w := &models.Webhook{}
+		if err := utils.ReadRequest(c, w); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+		w.WebhookID = wID
+
+		if err = h.webhookUC.Update(c.Request().Context(), w); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *webhookHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		wID, err := uuid.Parse(c.Param("webhook_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		if err = h.webhookUC.Delete(c.Request().Context(), wID); err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func (h *webhookHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		wID, err := uuid.Parse(c.Param("webhook_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		w, err := h.webhookUC.GetByID(c.Request().Context(), wID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+func (h *webhookHandlers) GetWebhooks() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		webhooks, err := h.webhookUC.GetWebhooks(c.Request().Context())
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, webhooks)
+	}
+}
+
+func (h *webhookHandlers) GetDeliveries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		wID, err := uuid.Parse(c.Param("webhook_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		deliveries, err := h.webhookUC.GetDeliveries(c.Request().Context(), wID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, deliveries)
+	}
+}
+
+func (h *webhookHandlers) Redeliver() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		wID, err := uuid.Parse(c.Param("webhook_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+		dID, err := uuid.Parse(c.Param("delivery_id"))
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, httpErrors.BadQueryParams)
+		}
+
+		delivery, err := h.webhookUC.Redeliver(c.Request().Context(), wID, dID)
+		if err != nil {
+			return utils.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, delivery)
+	}
+}
@@ -0,0 +1,20 @@
+package http
+
+import (
+	"equiptrack/internal/middleware"
+	"equiptrack/internal/models"
+	"equiptrack/internal/webhook"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MapWebhookRoutes(webhookGroup *echo.Group, h webhook.Handlers, mw *middleware.MiddlewareManager) {
+	webhookGroup.Use(mw.AuthJWTMiddleware, mw.IsAdminMiddleware, mw.RequireScope(models.ScopeUsersWrite))
+	webhookGroup.POST("", h.Create())
+	webhookGroup.GET("", h.GetWebhooks())
+	webhookGroup.GET("/:webhook_id", h.GetByID())
+	webhookGroup.PUT("/:webhook_id", h.Update())
+	webhookGroup.DELETE("/:webhook_id", h.Delete())
+	webhookGroup.GET("/:webhook_id/deliveries", h.GetDeliveries())
+	webhookGroup.POST("/:webhook_id/deliveries/:delivery_id/redeliver", h.Redeliver())
+}
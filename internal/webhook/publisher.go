@@ -0,0 +1,9 @@
+package webhook
+
+import "context"
+
+// Emits domain events to subscribed webhooks. Publishing never fails the caller,
+// delivery problems are handled by retries
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, data interface{})
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"equiptrack/internal/models"
+	"equiptrack/internal/webhook"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type webhookRepo struct {
+	db *sql.DB
+}
+
+// Webhook Repository constructor
+func NewWebhookRepository(db *sql.DB) webhook.Repository {
+	return &webhookRepo{db: db}
+}
+
+func (r *webhookRepo) Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	w := *webhook
+	if err := r.db.QueryRowContext(
+		ctx, qCreateWebhook, w.URL, w.Description, w.Secret, pq.Array(w.Events), w.Active,
+	).Scan(&w.WebhookID, &w.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "webhookRepo.Create.QueryRowContext")
+	}
+	return &w, nil
+}
+
+func (r *webhookRepo) Update(ctx context.Context, webhook *models.Webhook) error {
+	result, err := r.db.ExecContext(
+		ctx, qUpdateWebhook, webhook.URL, webhook.Description, pq.Array(webhook.Events), webhook.Active, webhook.WebhookID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "webhookRepo.Update.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "webhookRepo.Update.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "webhookRepo.Update.rowsAffected")
+	}
+	return nil
+}
+
+func (r *webhookRepo) Delete(ctx context.Context, webhookID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, qDeleteWebhook, webhookID)
+	if err != nil {
+		return errors.Wrap(err, "webhookRepo.Delete.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "webhookRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "webhookRepo.Delete.rowsAffected")
+	}
+	return nil
+}
+
+func (r *webhookRepo) GetByID(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error) {
+	w := &models.Webhook{}
+	if err := r.db.QueryRowContext(ctx, qGetWebhook, webhookID).Scan(
+		&w.WebhookID,
+		&w.URL,
+		&w.Description,
+		&w.Secret,
+		pq.Array(&w.Events),
+		&w.Active,
+		&w.CreatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "webhookRepo.GetByID.QueryRowContext")
+	}
+	return w, nil
+}
+
+func (r *webhookRepo) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return r.getWebhooks(ctx, "webhookRepo.GetWebhooks", qGetWebhooks)
+}
+
+func (r *webhookRepo) GetActiveWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return r.getWebhooks(ctx, "webhookRepo.GetActiveWebhooks", qGetActiveWebhooks)
+}
+
+func (r *webhookRepo) getWebhooks(ctx context.Context, op string, query string) ([]models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, op+".QueryContext")
+	}
+	defer rows.Close()
+
+	var webhooks = make([]models.Webhook, 0)
+	for rows.Next() {
+		var w models.Webhook
+		if err := rows.Scan(
+			&w.WebhookID,
+			&w.URL,
+			&w.Description,
+			&w.Secret,
+			pq.Array(&w.Events),
+			&w.Active,
+			&w.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, op+".ScanRows")
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepo) CreateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	if err := r.db.QueryRowContext(
+		ctx, qCreateDelivery, d.WebhookID, d.EventID, d.EventType, []byte(d.Payload),
+	).Scan(&d.DeliveryID, &d.Status, &d.CreatedAt, &d.NextAttemptAt); err != nil {
+		return errors.Wrap(err, "webhookRepo.CreateDelivery.QueryRowContext")
+	}
+	return nil
+}
+
+func (r *webhookRepo) GetDelivery(ctx context.Context, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	d, err := scanDelivery(r.db.QueryRowContext(ctx, qGetDelivery, deliveryID))
+	if err != nil {
+		return nil, errors.Wrap(err, "webhookRepo.GetDelivery.QueryRowContext")
+	}
+	return d, nil
+}
+
+func (r *webhookRepo) GetDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, qGetDeliveries, webhookID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "webhookRepo.GetDeliveries.QueryContext")
+	}
+	defer rows.Close()
+
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "webhookRepo.GetDeliveries.ScanRows")
+	}
+	return deliveries, nil
+}
+
+// Reports false when the delivery isn't due or another worker already claimed it
+func (r *webhookRepo) ClaimDelivery(ctx context.Context, deliveryID uuid.UUID, lease time.Duration) (bool, error) {
+	result, err := r.db.ExecContext(ctx, qClaimDelivery, deliveryID, lease.Seconds())
+	if err != nil {
+		return false, errors.Wrap(err, "webhookRepo.ClaimDelivery.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "webhookRepo.ClaimDelivery.RowsAffected")
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *webhookRepo) ClaimDueDeliveries(ctx context.Context, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, qClaimDueDeliveries, lease.Seconds(), limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "webhookRepo.ClaimDueDeliveries.QueryContext")
+	}
+	defer rows.Close()
+
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "webhookRepo.ClaimDueDeliveries.ScanRows")
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepo) RecordAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	if _, err := r.db.ExecContext(
+		ctx, qRecordAttempt,
+		d.Status, d.Attempts, d.ResponseStatus, d.Error, d.NextAttemptAt, d.DeliveredAt, d.DeliveryID,
+	); err != nil {
+		return errors.Wrap(err, "webhookRepo.RecordAttempt.ExecContext")
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDelivery(row scanner) (*models.WebhookDelivery, error) {
+	d := &models.WebhookDelivery{}
+	var payload []byte
+	if err := row.Scan(
+		&d.DeliveryID,
+		&d.WebhookID,
+		&d.EventID,
+		&d.EventType,
+		&payload,
+		&d.Status,
+		&d.Attempts,
+		&d.ResponseStatus,
+		&d.Error,
+		&d.NextAttemptAt,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	); err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	return d, nil
+}
+
+func scanDeliveries(rows *sql.Rows) ([]models.WebhookDelivery, error) {
+	var deliveries = make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,53 @@
+package repository
+
+const (
+	qCreateWebhook = `INSERT INTO webhooks (url, description, secret, events, active)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING webhook_id, created_at`
+	qUpdateWebhook = `UPDATE webhooks
+	SET url = $1, description = $2, events = $3, active = $4
+	WHERE webhook_id = $5`
+	qDeleteWebhook = `DELETE FROM webhooks WHERE webhook_id = $1`
+	qGetWebhook    = `SELECT webhook_id, url, description, secret, events, active, created_at
+	FROM webhooks
+	WHERE webhook_id = $1`
+	qGetWebhooks = `SELECT webhook_id, url, description, secret, events, active, created_at
+	FROM webhooks
+	ORDER BY created_at`
+	qGetActiveWebhooks = `SELECT webhook_id, url, description, secret, events, active, created_at
+	FROM webhooks
+	WHERE active`
+
+	qCreateDelivery = `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at)
+	VALUES ($1, $2, $3, $4, 'pending', CURRENT_TIMESTAMP)
+	RETURNING delivery_id, status, created_at, next_attempt_at`
+	qGetDelivery = `SELECT delivery_id, webhook_id, event_id, event_type, payload, status, attempts,
+		response_status, COALESCE(error, ''), next_attempt_at, created_at, delivered_at
+	FROM webhook_deliveries
+	WHERE delivery_id = $1`
+	qGetDeliveries = `SELECT delivery_id, webhook_id, event_id, event_type, payload, status, attempts,
+		response_status, COALESCE(error, ''), next_attempt_at, created_at, delivered_at
+	FROM webhook_deliveries
+	WHERE webhook_id = $1
+	ORDER BY created_at DESC
+	LIMIT $2`
+	// claiming pushes next attempt past the lease so no other worker picks the delivery meanwhile
+	qClaimDelivery = `UPDATE webhook_deliveries
+	SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+	WHERE delivery_id = $1 AND status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP`
+	qClaimDueDeliveries = `UPDATE webhook_deliveries
+	SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $1)
+	WHERE delivery_id IN (
+		SELECT delivery_id
+		FROM webhook_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY next_attempt_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING delivery_id, webhook_id, event_id, event_type, payload, status, attempts,
+		response_status, COALESCE(error, ''), next_attempt_at, created_at, delivered_at`
+	qRecordAttempt = `UPDATE webhook_deliveries
+	SET status = $1, attempts = $2, response_status = $3, error = NULLIF($4, ''), next_attempt_at = $5, delivered_at = $6
+	WHERE delivery_id = $7`
+)
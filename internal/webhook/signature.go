@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	HeaderEvent     = "X-Equiptrack-Event"
+	HeaderDelivery  = "X-Equiptrack-Delivery"
+	HeaderTimestamp = "X-Equiptrack-Timestamp"
+	HeaderSignature = "X-Equiptrack-Signature"
+)
+
+// HMAC-SHA256 over "<timestamp>.<body>", receivers recompute it with the shared
+// secret and reject old timestamps to prevent replays
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"context"
+	"equiptrack/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type UseCase interface {
+	Publisher
+	Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, webhookID uuid.UUID) error
+	GetByID(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, webhookID uuid.UUID, deliveryID uuid.UUID) (*models.WebhookDelivery, error)
+	ProcessDueDeliveries(ctx context.Context) error
+}
@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"equiptrack/config"
+	"equiptrack/internal/audit"
+	httpErrors "equiptrack/internal/httpErrors"
+	"equiptrack/internal/models"
+	"equiptrack/internal/utils"
+	"equiptrack/internal/webhook"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	deliveriesLimit = 100
+	// longest kept part of the receiver's error response
+	maxErrorLength = 500
+)
+
+type webhookUC struct {
+	cfg         *config.Config
+	webhookRepo webhook.Repository
+	client      *http.Client
+	auditor     audit.Recorder
+	logger      *logrus.Logger
+}
+
+func NewWebhookUseCase(
+	cfg *config.Config,
+	webhookRepo webhook.Repository,
+	auditor audit.Recorder,
+	log *logrus.Logger,
+) webhook.UseCase {
+	return &webhookUC{
+		cfg:         cfg,
+		webhookRepo: webhookRepo,
+		client:      &http.Client{Timeout: time.Second * cfg.Webhook.Timeout},
+		auditor:     auditor,
+		logger:      log,
+	}
+}
+
+// New webhooks are active, a secret is generated unless given
+func (u *webhookUC) Create(ctx context.Context, w *models.Webhook) (*models.Webhook, error) {
+	if err := validateEvents(w.Events); err != nil {
+		return nil, err
+	}
+	if w.Secret == "" {
+		secret, err := utils.NewSecureToken()
+		if err != nil {
+			return nil, errors.Wrap(err, "webhookUC.Create.NewSecureToken")
+		}
+		w.Secret = secret
+	}
+	w.Active = true
+	created, err := u.webhookRepo.Create(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	u.auditor.Record(ctx, models.AuditCreate, models.AuditEntityWebhook, created.WebhookID.String(), nil, created)
+	return created, nil
+}
+
+func (u *webhookUC) Update(ctx context.Context, w *models.Webhook) error {
+	if err := validateEvents(w.Events); err != nil {
+		return err
+	}
+	before, err := u.webhookRepo.GetByID(ctx, w.WebhookID)
+	if err != nil {
+		return err
+	}
+	if err = u.webhookRepo.Update(ctx, w); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditUpdate, models.AuditEntityWebhook, w.WebhookID.String(), before, w)
+	return nil
+}
+
+func (u *webhookUC) Delete(ctx context.Context, webhookID uuid.UUID) error {
+	before, err := u.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+	if err = u.webhookRepo.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+	u.auditor.Record(ctx, models.AuditDelete, models.AuditEntityWebhook, webhookID.String(), before, nil)
+	return nil
+}
+
+func (u *webhookUC) GetByID(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error) {
+	w, err := u.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	w.Secret = ""
+	return w, nil
+}
+
+func (u *webhookUC) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := u.webhookRepo.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (u *webhookUC) GetDeliveries(ctx context.Context, webhookID uuid.UUID) ([]models.WebhookDelivery, error) {
+	if _, err := u.webhookRepo.GetByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	return u.webhookRepo.GetDeliveries(ctx, webhookID, deliveriesLimit)
+}
+
+// Sends the payload of a past delivery again as a new delivery, attempted right away
+func (u *webhookUC) Redeliver(ctx context.Context, webhookID uuid.UUID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	w, err := u.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := u.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if prev.WebhookID != webhookID {
+		return nil, httpErrors.NewNotFoundError("delivery of another webhook")
+	}
+
+	d := &models.WebhookDelivery{
+		WebhookID: webhookID,
+		EventID:   prev.EventID,
+		EventType: prev.EventType,
+		Payload:   prev.Payload,
+	}
+	if err = u.webhookRepo.CreateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	claimed, err := u.webhookRepo.ClaimDelivery(ctx, d.DeliveryID, u.lease())
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		u.deliver(ctx, w, d)
+	}
+	return d, nil
+}
+
+// Stores a delivery for every subscribed webhook and attempts them in the background,
+// failed ones are picked up by ProcessDueDeliveries
+func (u *webhookUC) Publish(ctx context.Context, eventType string, data interface{}) {
+	webhooks, err := u.webhookRepo.GetActiveWebhooks(ctx)
+	if err != nil {
+		u.logger.Errorf("webhookUC.Publish.GetActiveWebhooks: %v", err)
+		return
+	}
+
+	event := &models.WebhookEvent{EventID: uuid.New(), Type: eventType, OccurredAt: time.Now().UTC(), Data: data}
+	var payload []byte
+	for i := range webhooks {
+		w := webhooks[i]
+		if !w.Subscribed(eventType) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				u.logger.Errorf("webhookUC.Publish.Marshal: %v", err)
+				return
+			}
+		}
+
+		d := &models.WebhookDelivery{WebhookID: w.WebhookID, EventID: event.EventID, EventType: eventType, Payload: payload}
+		if err := u.webhookRepo.CreateDelivery(ctx, d); err != nil {
+			u.logger.Errorf("webhookUC.Publish.CreateDelivery: %v", err)
+			continue
+		}
+		go u.attempt(context.WithoutCancel(ctx), &w, d)
+	}
+}
+
+func (u *webhookUC) attempt(ctx context.Context, w *models.Webhook, d *models.WebhookDelivery) {
+	claimed, err := u.webhookRepo.ClaimDelivery(ctx, d.DeliveryID, u.lease())
+	if err != nil {
+		u.logger.Errorf("webhookUC.attempt.ClaimDelivery: %v", err)
+		return
+	}
+	if claimed {
+		u.deliver(ctx, w, d)
+	}
+}
+
+// Scheduled job retrying deliveries whose backoff elapsed
+func (u *webhookUC) ProcessDueDeliveries(ctx context.Context) error {
+	deliveries, err := u.webhookRepo.ClaimDueDeliveries(ctx, u.lease(), deliveriesLimit)
+	if err != nil {
+		return err
+	}
+
+	webhooks := make(map[uuid.UUID]*models.Webhook)
+	for i := range deliveries {
+		d := &deliveries[i]
+		w, ok := webhooks[d.WebhookID]
+		if !ok {
+			if w, err = u.webhookRepo.GetByID(ctx, d.WebhookID); err != nil {
+				return err
+			}
+			webhooks[d.WebhookID] = w
+		}
+		u.deliver(ctx, w, d)
+	}
+	return nil
+}
+
+// Post the delivery and store the outcome, scheduling the next attempt on failure
+func (u *webhookUC) deliver(ctx context.Context, w *models.Webhook, d *models.WebhookDelivery) {
+	now := time.Now()
+	d.Attempts++
+	d.ResponseStatus = nil
+	d.Error = ""
+
+	if !w.Active {
+		d.Error = "webhook is disabled"
+	} else if status, err := u.post(ctx, w, d, now); err != nil {
+		d.Error = err.Error()
+		if status != 0 {
+			d.ResponseStatus = &status
+		}
+	} else {
+		d.ResponseStatus = &status
+	}
+
+	switch {
+	case d.Error == "":
+		d.Status = models.DeliverySucceeded
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+	case !w.Active || d.Attempts >= u.cfg.Webhook.MaxAttempts:
+		d.Status = models.DeliveryFailed
+		d.NextAttemptAt = nil
+	default:
+		next := now.Add(time.Second * u.cfg.Webhook.BackoffBase << (d.Attempts - 1))
+		d.Status = models.DeliveryPending
+		d.NextAttemptAt = &next
+	}
+
+	if err := u.webhookRepo.RecordAttempt(ctx, d); err != nil {
+		u.logger.Errorf("webhookUC.deliver.RecordAttempt: %v", err)
+	}
+}
+
+func (u *webhookUC) post(ctx context.Context, w *models.Webhook, d *models.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderEvent, d.EventType)
+	req.Header.Set(webhook.HeaderDelivery, d.DeliveryID.String())
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(w.Secret, timestamp, d.Payload))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+		return resp.StatusCode, fmt.Errorf("receiver responded %s: %s", resp.Status, body)
+	}
+	return resp.StatusCode, nil
+}
+
+// How long a claimed delivery is hidden from other workers
+func (u *webhookUC) lease() time.Duration {
+	return 2*time.Second*u.cfg.Webhook.Timeout + time.Minute
+}
+
+func validateEvents(events []string) error {
+	for _, e := range events {
+		known := false
+		for _, k := range models.WebhookEvents {
+			if e == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return httpErrors.NewBadRequestError("unknown event type " + e)
+		}
+	}
+	return nil
+}